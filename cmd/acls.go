@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ACLSpec is the ACLs to create, or the filter of the ACLs to display or delete, set by the ACL flags
+type ACLSpec struct {
+	Principals    []string
+	Hosts         []string
+	ResourceType  kmsg.ACLResourceType
+	ResourceNames []string
+	Pattern       kadm.ACLPattern
+	Operations    []kadm.ACLOperation
+	Permission    kmsg.ACLPermissionType
+}
+
+// ACLFilterFlags are the flags of AddACLFlags, at least one of which must be set to delete ACLs
+var ACLFilterFlags = []string{"principal", "host", "resource-type", "resource-name", "pattern-type", "operation", "permission"}
+
+// AddACLFlags adds the flags of the ACLs to create, or of the filter of the ACLs to display or delete, in which
+// unset flags match anything
+func AddACLFlags(command *cobra.Command, create bool) {
+	if create {
+		command.Flags().StringSlice("principal", []string{}, "Principals of the ACLs, example: User:alice. Principals without a type are users")
+		command.Flags().StringSlice("host", []string{"*"}, "Hosts the principals connect from")
+		command.Flags().String("resource-type", "", "Type of the resource: topic, group, cluster, transactional-id or delegation-token")
+		command.Flags().StringSlice("resource-name", []string{}, "Names of the resources, or their prefix with --pattern-type prefixed")
+		command.Flags().String("pattern-type", "literal", "Pattern type of the resource names: literal or prefixed")
+		command.Flags().StringSlice("operation", []string{}, "Operations: all, read, write, create, delete, alter, describe, cluster-action, describe-configs, alter-configs or idempotent-write")
+		command.Flags().String("permission", "allow", "Permission: allow or deny")
+		for _, flag := range []string{"principal", "resource-type", "operation"} {
+			cobra.CheckErr(command.MarkFlagRequired(flag))
+		}
+		return
+	}
+	command.Flags().StringSlice("principal", []string{}, "Filter ACLs by principal, example: User:alice. Principals without a type are users")
+	command.Flags().StringSlice("host", []string{}, "Filter ACLs by host")
+	command.Flags().String("resource-type", "any", "Filter ACLs by type of resource: any, topic, group, cluster, transactional-id or delegation-token")
+	command.Flags().StringSlice("resource-name", []string{}, "Filter ACLs by name of resource")
+	command.Flags().String("pattern-type", "any", "Filter ACLs by pattern type: any, literal, prefixed, or match for the ACLs which apply to --resource-name")
+	command.Flags().StringSlice("operation", []string{}, "Filter ACLs by operation: all, read, write, create, delete, alter, describe, cluster-action, describe-configs, alter-configs or idempotent-write")
+	command.Flags().String("permission", "any", "Filter ACLs by permission: any, allow or deny")
+}
+
+// GetACLFlags returns the ACLs to create, or the filter of the ACLs to display or delete, of the flags of AddACLFlags
+func GetACLFlags(command *cobra.Command, create bool) (ACLSpec, error) {
+	var spec ACLSpec
+	principals, err := command.Flags().GetStringSlice("principal")
+	cobra.CheckErr(err)
+	for _, principal := range principals {
+		if principal == "" {
+			return ACLSpec{}, fmt.Errorf("invalid empty --principal")
+		}
+		if !strings.Contains(principal, ":") {
+			principal = "User:" + principal
+		}
+		spec.Principals = append(spec.Principals, principal)
+	}
+	spec.Hosts, err = command.Flags().GetStringSlice("host")
+	cobra.CheckErr(err)
+	spec.ResourceNames, err = command.Flags().GetStringSlice("resource-name")
+	cobra.CheckErr(err)
+
+	resourceType, err := command.Flags().GetString("resource-type")
+	cobra.CheckErr(err)
+	spec.ResourceType, err = kmsg.ParseACLResourceType(resourceType)
+	if err != nil || spec.ResourceType == kmsg.ACLResourceTypeUnknown || spec.ResourceType == kmsg.ACLResourceTypeUser || create && spec.ResourceType == kmsg.ACLResourceTypeAny {
+		return ACLSpec{}, fmt.Errorf("invalid --resource-type '%s'", resourceType)
+	}
+	switch spec.ResourceType {
+	case kmsg.ACLResourceTypeCluster:
+		if slices.ContainsFunc(spec.ResourceNames, func(name string) bool { return name != "kafka-cluster" }) {
+			return ACLSpec{}, fmt.Errorf("the resource of type cluster has no name, or the name kafka-cluster")
+		}
+	case kmsg.ACLResourceTypeAny:
+	default:
+		if create && len(spec.ResourceNames) == 0 {
+			return ACLSpec{}, fmt.Errorf("--resource-name is required to create ACLs of resource type %s", resourceType)
+		}
+	}
+
+	patternType, err := command.Flags().GetString("pattern-type")
+	cobra.CheckErr(err)
+	spec.Pattern, err = kmsg.ParseACLResourcePatternType(patternType)
+	if err != nil || spec.Pattern == kadm.ACLPatternUnknown || create && spec.Pattern != kadm.ACLPatternLiteral && spec.Pattern != kadm.ACLPatternPrefixed {
+		return ACLSpec{}, fmt.Errorf("invalid --pattern-type '%s'", patternType)
+	}
+
+	operations, err := command.Flags().GetStringSlice("operation")
+	cobra.CheckErr(err)
+	for _, operation := range operations {
+		parsed, err := kmsg.ParseACLOperation(operation)
+		if err != nil || parsed == kadm.OpUnknown || create && parsed == kadm.OpAny {
+			return ACLSpec{}, fmt.Errorf("invalid --operation '%s'", operation)
+		}
+		spec.Operations = append(spec.Operations, parsed)
+	}
+
+	permission, err := command.Flags().GetString("permission")
+	cobra.CheckErr(err)
+	spec.Permission, err = kmsg.ParseACLPermissionType(permission)
+	if err != nil || spec.Permission == kmsg.ACLPermissionTypeUnknown || create && spec.Permission == kmsg.ACLPermissionTypeAny {
+		return ACLSpec{}, fmt.Errorf("invalid --permission '%s'", permission)
+	}
+	return spec, nil
+}
+
+// Builder returns the ACL builder of the spec, in which empty principals, hosts, resource names and operations match
+// anything when used as a filter
+func (s ACLSpec) Builder() *kadm.ACLBuilder {
+	builder := kadm.NewACLs().ResourcePatternType(s.Pattern).Operations(s.Operations...)
+	switch s.ResourceType {
+	case kmsg.ACLResourceTypeTopic:
+		builder.Topics(s.ResourceNames...)
+	case kmsg.ACLResourceTypeGroup:
+		builder.Groups(s.ResourceNames...)
+	case kmsg.ACLResourceTypeCluster:
+		builder.Clusters()
+	case kmsg.ACLResourceTypeTransactionalId:
+		builder.TransactionalIDs(s.ResourceNames...)
+	case kmsg.ACLResourceTypeDelegationToken:
+		builder.DelegationTokens(s.ResourceNames...)
+	default:
+		builder.AnyResource(s.ResourceNames...)
+	}
+	if s.Permission != kmsg.ACLPermissionTypeDeny {
+		builder.Allow(s.Principals...).AllowHosts(s.Hosts...)
+	}
+	if s.Permission != kmsg.ACLPermissionTypeAllow {
+		builder.Deny(s.Principals...).DenyHosts(s.Hosts...)
+	}
+	return builder
+}
+
+// DescribeACLs returns the ACLs matching the filter of the builder
+func DescribeACLs(ctx context.Context, adminClient *kadm.Client, builder *kadm.ACLBuilder) ([]kadm.DescribedACL, error) {
+	results, err := adminClient.DescribeACLs(ctx, builder)
+	if err != nil {
+		return nil, fmt.Errorf("error describing ACLs: %v", err)
+	}
+	var acls []kadm.DescribedACL
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("error describing ACLs: %v: %s", result.Err, result.ErrMessage)
+		}
+		acls = append(acls, result.Described...)
+	}
+	return acls, nil
+}
+
+// FormatACLEnum renders a resource type, pattern type, operation or permission of an ACL like the flags, example:
+// describe-configs
+func FormatACLEnum(value fmt.Stringer) string {
+	return strings.ToLower(strings.ReplaceAll(value.String(), "_", "-"))
+}
+
+// PrintACLs prints a table of ACLs, sorted by resource
+func PrintACLs(out io.Writer, acls []kadm.DescribedACL) error {
+	acls = slices.Clone(acls)
+	slices.SortFunc(acls, func(a, b kadm.DescribedACL) int {
+		if a.Type != b.Type {
+			return int(a.Type) - int(b.Type)
+		}
+		if diff := strings.Compare(a.Name, b.Name); diff != 0 {
+			return diff
+		}
+		if a.Pattern != b.Pattern {
+			return int(a.Pattern) - int(b.Pattern)
+		}
+		if diff := strings.Compare(a.Principal, b.Principal); diff != 0 {
+			return diff
+		}
+		if diff := strings.Compare(a.Host, b.Host); diff != 0 {
+			return diff
+		}
+		if a.Operation != b.Operation {
+			return int(a.Operation) - int(b.Operation)
+		}
+		return int(a.Permission) - int(b.Permission)
+	})
+
+	_, err := fmt.Fprintf(out, "%-30s%-20s%-20s%-30s%-12s%-20s%-12s\n", "Principal", "Host", "Resource type", "Resource name", "Pattern", "Operation", "Permission")
+	if err != nil {
+		return err
+	}
+	for _, acl := range acls {
+		_, err := fmt.Fprintf(out, "%-30s%-20s%-20s%-30s%-12s%-20s%-12s\n", acl.Principal, acl.Host, FormatACLEnum(acl.Type),
+			acl.Name, FormatACLEnum(acl.Pattern), FormatACLEnum(acl.Operation), FormatACLEnum(acl.Permission))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestGetACLFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		create        bool
+		flags         map[string]string
+		expectedSpec  ACLSpec
+		expectedError string
+	}{
+		{
+			name:   "filter defaults match anything",
+			create: false,
+			expectedSpec: ACLSpec{
+				Hosts:         []string{},
+				ResourceType:  kmsg.ACLResourceTypeAny,
+				ResourceNames: []string{},
+				Pattern:       kadm.ACLPatternAny,
+				Permission:    kmsg.ACLPermissionTypeAny,
+			},
+		},
+		{
+			name:   "filter with every flag",
+			create: false,
+			flags: map[string]string{
+				"principal":     "alice,Group:billing",
+				"host":          "10.0.0.1",
+				"resource-type": "transactional-id",
+				"resource-name": "billing-1,billing-2",
+				"pattern-type":  "match",
+				"operation":     "describe,DESCRIBE_CONFIGS",
+				"permission":    "deny",
+			},
+			expectedSpec: ACLSpec{
+				Principals:    []string{"User:alice", "Group:billing"},
+				Hosts:         []string{"10.0.0.1"},
+				ResourceType:  kmsg.ACLResourceTypeTransactionalId,
+				ResourceNames: []string{"billing-1", "billing-2"},
+				Pattern:       kadm.ACLPatternMatch,
+				Operations:    []kadm.ACLOperation{kadm.OpDescribe, kadm.OpDescribeConfigs},
+				Permission:    kmsg.ACLPermissionTypeDeny,
+			},
+		},
+		{
+			name:   "create defaults to literal ACLs allowing any host",
+			create: true,
+			flags: map[string]string{
+				"principal":     "User:alice",
+				"resource-type": "topic",
+				"resource-name": "orders",
+				"operation":     "read",
+			},
+			expectedSpec: ACLSpec{
+				Principals:    []string{"User:alice"},
+				Hosts:         []string{"*"},
+				ResourceType:  kmsg.ACLResourceTypeTopic,
+				ResourceNames: []string{"orders"},
+				Pattern:       kadm.ACLPatternLiteral,
+				Operations:    []kadm.ACLOperation{kadm.OpRead},
+				Permission:    kmsg.ACLPermissionTypeAllow,
+			},
+		},
+		{
+			name:   "create cluster ACLs without a name",
+			create: true,
+			flags: map[string]string{
+				"principal":     "alice",
+				"resource-type": "cluster",
+				"operation":     "cluster-action",
+			},
+			expectedSpec: ACLSpec{
+				Principals:    []string{"User:alice"},
+				Hosts:         []string{"*"},
+				ResourceType:  kmsg.ACLResourceTypeCluster,
+				ResourceNames: []string{},
+				Pattern:       kadm.ACLPatternLiteral,
+				Operations:    []kadm.ACLOperation{kadm.OpClusterAction},
+				Permission:    kmsg.ACLPermissionTypeAllow,
+			},
+		},
+		{
+			name:          "unknown resource type",
+			flags:         map[string]string{"resource-type": "table"},
+			expectedError: "invalid --resource-type 'table'",
+		},
+		{
+			name:          "user resource type",
+			flags:         map[string]string{"resource-type": "user"},
+			expectedError: "invalid --resource-type 'user'",
+		},
+		{
+			name:          "cluster with another name",
+			flags:         map[string]string{"resource-type": "cluster", "resource-name": "production"},
+			expectedError: "the resource of type cluster has no name, or the name kafka-cluster",
+		},
+		{
+			name:          "unknown operation",
+			flags:         map[string]string{"operation": "read,publish"},
+			expectedError: "invalid --operation 'publish'",
+		},
+		{
+			name:          "unknown permission",
+			flags:         map[string]string{"permission": "maybe"},
+			expectedError: "invalid --permission 'maybe'",
+		},
+		{
+			name:          "create any resource type",
+			create:        true,
+			flags:         map[string]string{"principal": "alice", "resource-type": "any", "operation": "read"},
+			expectedError: "invalid --resource-type 'any'",
+		},
+		{
+			name:          "create without resource name",
+			create:        true,
+			flags:         map[string]string{"principal": "alice", "resource-type": "group", "operation": "read"},
+			expectedError: "--resource-name is required to create ACLs of resource type group",
+		},
+		{
+			name:          "create with match pattern type",
+			create:        true,
+			flags:         map[string]string{"principal": "alice", "resource-type": "topic", "resource-name": "orders", "pattern-type": "match", "operation": "read"},
+			expectedError: "invalid --pattern-type 'match'",
+		},
+		{
+			name:          "create with any operation",
+			create:        true,
+			flags:         map[string]string{"principal": "alice", "resource-type": "topic", "resource-name": "orders", "operation": "any"},
+			expectedError: "invalid --operation 'any'",
+		},
+		{
+			name:          "create with any permission",
+			create:        true,
+			flags:         map[string]string{"principal": "alice", "resource-type": "topic", "resource-name": "orders", "operation": "read", "permission": "any"},
+			expectedError: "invalid --permission 'any'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := &cobra.Command{}
+			AddACLFlags(command, tt.create)
+			for flag, value := range tt.flags {
+				assert.NoError(t, command.Flags().Set(flag, value))
+			}
+
+			spec, err := GetACLFlags(command, tt.create)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSpec, spec)
+		})
+	}
+}
+
+func TestACLSpecBuilder(t *testing.T) {
+	filter := ACLSpec{ResourceType: kmsg.ACLResourceTypeAny, Pattern: kadm.ACLPatternAny, Permission: kmsg.ACLPermissionTypeAny}
+	assert.NoError(t, filter.Builder().ValidateFilter())
+	assert.True(t, filter.Builder().HasAnyFilter())
+
+	filter = ACLSpec{Principals: []string{"User:alice"}, ResourceType: kmsg.ACLResourceTypeTopic, Pattern: kadm.ACLPatternAny, Permission: kmsg.ACLPermissionTypeAny}
+	assert.NoError(t, filter.Builder().ValidateFilter())
+
+	create := ACLSpec{
+		Principals:    []string{"User:alice"},
+		Hosts:         []string{"*"},
+		ResourceType:  kmsg.ACLResourceTypeGroup,
+		ResourceNames: []string{"billing"},
+		Pattern:       kadm.ACLPatternPrefixed,
+		Operations:    []kadm.ACLOperation{kadm.OpRead},
+		Permission:    kmsg.ACLPermissionTypeDeny,
+	}
+	assert.NoError(t, create.Builder().ValidateCreate())
+	assert.True(t, create.Builder().HasResource())
+	assert.True(t, create.Builder().HasPrincipals())
+	assert.False(t, create.Builder().HasAnyFilter())
+}
+
+func TestFormatACLEnum(t *testing.T) {
+	assert.Equal(t, "transactional-id", FormatACLEnum(kmsg.ACLResourceTypeTransactionalId))
+	assert.Equal(t, "describe-configs", FormatACLEnum(kadm.OpDescribeConfigs))
+	assert.Equal(t, "prefixed", FormatACLEnum(kadm.ACLPatternPrefixed))
+	assert.Equal(t, "allow", FormatACLEnum(kmsg.ACLPermissionTypeAllow))
+}
+
+func TestPrintACLs(t *testing.T) {
+	acls := []kadm.DescribedACL{
+		{Principal: "User:bob", Host: "*", Type: kmsg.ACLResourceTypeTopic, Name: "orders", Pattern: kadm.ACLPatternLiteral, Operation: kadm.OpWrite, Permission: kmsg.ACLPermissionTypeAllow},
+		{Principal: "User:alice", Host: "*", Type: kmsg.ACLResourceTypeGroup, Name: "billing-", Pattern: kadm.ACLPatternPrefixed, Operation: kadm.OpRead, Permission: kmsg.ACLPermissionTypeDeny},
+		{Principal: "User:alice", Host: "*", Type: kmsg.ACLResourceTypeTopic, Name: "orders", Pattern: kadm.ACLPatternLiteral, Operation: kadm.OpRead, Permission: kmsg.ACLPermissionTypeAllow},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, PrintACLs(&out, acls))
+	assert.Regexp(t, regexp.MustCompile(`^Principal\s+Host\s+Resource type\s+Resource name\s+Pattern\s+Operation\s+Permission\s*\n`+
+		`User:alice\s+\*\s+topic\s+orders\s+literal\s+read\s+allow\s*\n`+
+		`User:bob\s+\*\s+topic\s+orders\s+literal\s+write\s+allow\s*\n`+
+		`User:alice\s+\*\s+group\s+billing-\s+prefixed\s+read\s+deny\s*\n$`), out.String())
+}
@@ -28,6 +28,12 @@ var getClustersCmd = &cobra.Command{
 
 		for clusterName, clusterConfig := range clusters {
 			bootstrapServers := clusterConfig.(map[string]interface{})["bootstrap-servers"]
+			schemaRegistry := clusterConfig.(map[string]interface{})["schema-registry"]
+			if schemaRegistry != nil && schemaRegistry != "" {
+				_, err := fmt.Fprintf(cmd.OutOrStdout(), "- %s: %v (schema registry: %v)\n", clusterName, bootstrapServers, schemaRegistry)
+				cobra.CheckErr(err)
+				continue
+			}
 			_, err := fmt.Fprintf(cmd.OutOrStdout(), "- %s: %v\n", clusterName, bootstrapServers)
 			cobra.CheckErr(err)
 		}
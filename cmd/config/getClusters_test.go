@@ -54,6 +54,20 @@ func TestGetClusters(t *testing.T) {
 - staging-cluster: [kafka-staging-1:9092 kafka-staging-2:9092]
 `,
 		},
+		{
+			name: "cluster with schema registry",
+			args: []string{"config", "get-clusters"},
+			testConfig: test_helpers.TestConfig{
+				Clusters: map[string]map[string]interface{}{
+					"test-cluster": {
+						"bootstrap-servers": []string{"localhost:9092"},
+						"schema-registry":   "http://localhost:8081",
+					},
+				},
+			},
+			expectedError:  false,
+			expectedOutput: "Clusters defined in the configuration:\n- test-cluster: [localhost:9092] (schema registry: http://localhost:8081)\n",
+		},
 		{
 			name: "clusters set but empty",
 			args: []string{"config", "get-clusters"},
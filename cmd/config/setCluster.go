@@ -2,8 +2,12 @@ package config
 
 import (
 	"fmt"
+	kacaoCmd "github.com/Vidalee/kacao/cmd"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"path/filepath"
+	"slices"
+	"strings"
 )
 
 var setClusterCmd = &cobra.Command{
@@ -15,7 +19,18 @@ For local development:
 - kacao config set-cluster local --bootstrap-servers localhost:9092
 
 For production:
-- kacao config set-cluster production --bootstrap-servers broker1:9092,broker2:9092,broker3:9092`,
+- kacao config set-cluster production --bootstrap-servers broker1:9092,broker2:9092,broker3:9092
+
+With a schema registry, used to decode messages, with optional basic authentication:
+- kacao config set-cluster production --bootstrap-servers broker1:9092 --schema-registry https://registry:8081 --schema-registry-username user --schema-registry-password password
+
+With topics whose values are raw Protobuf messages, decoded by consume and get messages without a schema registry.
+--proto-topic orders=shop.Order is a shorthand of --topic-value-decoder orders=protobuf:orders.pb:shop.Order:
+- kacao config set-cluster production --bootstrap-servers broker1:9092 --proto-descriptor-set orders.pb --proto-topic orders=shop.Order
+
+With the decoders of the keys and values of topics, used by consume and get messages when --key-decoder and
+--value-decoder are not set. Decoders are written like these flags, files being made absolute:
+- kacao config set-cluster production --bootstrap-servers broker1:9092 --topic-key-decoder orders=text --topic-value-decoder orders=avro:order.avsc --topic-value-decoder audit=exec:audit-decode`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		clusterName := args[0]
@@ -25,18 +40,167 @@ For production:
 		if !isValidClusterName(clusterName) {
 			return fmt.Errorf("cluster name can only contain alphanumerical characters, hyphens, and underscores, and must start with a letter")
 		}
+		topicDecoders, err := getTopicDecoderArgs(cmd)
+		if err != nil {
+			return err
+		}
 
 		_, err = fmt.Fprintf(cmd.OutOrStdout(), "Setting up cluster '%s' with bootstrap servers: %v\n", clusterName, bootstrapServers)
 		cobra.CheckErr(err)
 
 		viper.Set("clusters."+clusterName+".bootstrap-servers", bootstrapServers)
 
+		if cmd.Flags().Changed("schema-registry") {
+			schemaRegistry, err := cmd.Flags().GetString("schema-registry")
+			cobra.CheckErr(err)
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Setting up schema registry: %s\n", schemaRegistry)
+			cobra.CheckErr(err)
+			viper.Set("clusters."+clusterName+".schema-registry", schemaRegistry)
+		}
+		for _, flag := range []string{"schema-registry-username", "schema-registry-password"} {
+			if cmd.Flags().Changed(flag) {
+				value, err := cmd.Flags().GetString(flag)
+				cobra.CheckErr(err)
+				viper.Set("clusters."+clusterName+"."+flag, value)
+			}
+		}
+
+		if len(topicDecoders) > 0 {
+			if err := setTopicDecoders(cmd, clusterName, topicDecoders); err != nil {
+				return err
+			}
+		}
+
 		return viper.WriteConfig()
 	},
 }
 
+// topicDecoderArg is the decoder of the keys or values of a topic set by --topic-key-decoder or --topic-value-decoder
+type topicDecoderArg struct {
+	topic   string
+	field   string
+	decoder string
+}
+
+// getTopicDecoderArgs returns the decoders of --topic-key-decoder, --topic-value-decoder and --proto-topic, an empty
+// decoder removing the decoder of the topic
+func getTopicDecoderArgs(cmd *cobra.Command) ([]topicDecoderArg, error) {
+	var topicDecoders []topicDecoderArg
+	for _, field := range []string{"key", "value"} {
+		flag := "topic-" + field + "-decoder"
+		args, err := cmd.Flags().GetStringArray(flag)
+		cobra.CheckErr(err)
+		for _, arg := range args {
+			topic, decoder, ok := strings.Cut(arg, "=")
+			if !ok || topic == "" {
+				return nil, fmt.Errorf("invalid --%s '%s'. Expected topic=decoder", flag, arg)
+			}
+			if decoder != "" {
+				decoder, err = kacaoCmd.NormalizeDeserializerSpec(decoder)
+				if err != nil {
+					return nil, fmt.Errorf("invalid --%s '%s': %v", flag, arg, err)
+				}
+			}
+			topicDecoders = append(topicDecoders, topicDecoderArg{topic: topic, field: field, decoder: decoder})
+		}
+	}
+
+	protoTopics, err := getProtoTopicArgs(cmd)
+	if err != nil {
+		return nil, err
+	}
+	for _, protoTopic := range protoTopics {
+		if slices.ContainsFunc(topicDecoders, func(topicDecoder topicDecoderArg) bool {
+			return topicDecoder.topic == protoTopic.topic && topicDecoder.field == protoTopic.field
+		}) {
+			return nil, fmt.Errorf("the value decoder of topic '%s' is set by both --proto-topic and --topic-value-decoder", protoTopic.topic)
+		}
+		topicDecoders = append(topicDecoders, protoTopic)
+	}
+	return topicDecoders, nil
+}
+
+func setTopicDecoders(cmd *cobra.Command, clusterName string, updates []topicDecoderArg) error {
+	topicDecoders, err := kacaoCmd.GetClusterTopicDecoders(clusterName)
+	if err != nil {
+		return err
+	}
+	for _, update := range updates {
+		index := slices.IndexFunc(topicDecoders, func(topicDecoder kacaoCmd.TopicDecoders) bool {
+			return topicDecoder.Topic == update.topic
+		})
+		if index < 0 {
+			topicDecoders = append(topicDecoders, kacaoCmd.TopicDecoders{Topic: update.topic})
+			index = len(topicDecoders) - 1
+		}
+		if update.field == "key" {
+			topicDecoders[index].Key = update.decoder
+		} else {
+			topicDecoders[index].Value = update.decoder
+		}
+		if update.decoder == "" {
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Removing %s decoder of topic '%s'\n", update.field, update.topic)
+		} else {
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Setting up %s decoder of topic '%s': %s\n", update.field, update.topic, update.decoder)
+		}
+		cobra.CheckErr(err)
+	}
+
+	topicDecodersConfig := make([]map[string]string, 0, len(topicDecoders))
+	for _, topicDecoder := range topicDecoders {
+		if topicDecoder.Key == "" && topicDecoder.Value == "" {
+			continue
+		}
+		topicDecodersConfig = append(topicDecodersConfig, map[string]string{
+			"topic": topicDecoder.Topic,
+			"key":   topicDecoder.Key,
+			"value": topicDecoder.Value,
+		})
+	}
+	viper.Set("clusters."+clusterName+".topic-decoders", topicDecodersConfig)
+	return nil
+}
+
+// getProtoTopicArgs returns the value decoders of the topics of --proto-topic, protobuf decoders of
+// --proto-descriptor-set, an empty message removing the decoder of a topic
+func getProtoTopicArgs(cmd *cobra.Command) ([]topicDecoderArg, error) {
+	protoTopicArgs, err := cmd.Flags().GetStringArray("proto-topic")
+	cobra.CheckErr(err)
+	descriptorSet, err := cmd.Flags().GetString("proto-descriptor-set")
+	cobra.CheckErr(err)
+	if descriptorSet != "" {
+		// The configuration is used from any directory
+		descriptorSet, err = filepath.Abs(descriptorSet)
+		cobra.CheckErr(err)
+	}
+
+	protoTopics := make([]topicDecoderArg, 0, len(protoTopicArgs))
+	for _, protoTopicArg := range protoTopicArgs {
+		topic, message, ok := strings.Cut(protoTopicArg, "=")
+		if !ok || topic == "" {
+			return nil, fmt.Errorf("invalid --proto-topic '%s'. Expected topic=package.Message", protoTopicArg)
+		}
+		protoTopic := topicDecoderArg{topic: topic, field: "value"}
+		if message != "" {
+			if descriptorSet == "" {
+				return nil, fmt.Errorf("--proto-descriptor-set is required to set the Protobuf message of topic '%s'", topic)
+			}
+			protoTopic.decoder = kacaoCmd.DeserializerProtobuf + ":" + descriptorSet + ":" + message
+		}
+		protoTopics = append(protoTopics, protoTopic)
+	}
+	return protoTopics, nil
+}
+
 func init() {
 	setClusterCmd.Flags().StringSlice("bootstrap-servers", []string{}, "Comma-separated list of Kafka bootstrap servers")
+	setClusterCmd.Flags().String("schema-registry", "", "URL of the schema registry of the cluster")
+	setClusterCmd.Flags().String("schema-registry-username", "", "Username for the basic authentication of the schema registry")
+	setClusterCmd.Flags().String("schema-registry-password", "", "Password for the basic authentication of the schema registry")
+	setClusterCmd.Flags().String("proto-descriptor-set", "", "Protobuf descriptor set of the messages of --proto-topic")
+	setClusterCmd.Flags().StringArray("proto-topic", []string{}, "Decode the values of a topic as a Protobuf message of --proto-descriptor-set, example: --proto-topic orders=shop.Order. Use orders= to remove it")
+	setClusterCmd.Flags().StringArray("topic-key-decoder", []string{}, "Decoder of the keys of a topic, example: --topic-key-decoder orders=text. Use orders= to remove it")
+	setClusterCmd.Flags().StringArray("topic-value-decoder", []string{}, "Decoder of the values of a topic, example: --topic-value-decoder orders=avro:order.avsc. Use orders= to remove it")
 	err := setClusterCmd.MarkFlagRequired("bootstrap-servers")
 	cobra.CheckErr(err)
 	configCmd.AddCommand(setClusterCmd)
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/Vidalee/kacao/cmd"
 	"github.com/Vidalee/kacao/test_helpers"
 	"strings"
 	"testing"
@@ -90,6 +91,152 @@ func TestSetCluster(t *testing.T) {
 				assert.Equal(t, []string{"new-server:9092"}, bootstrapServers)
 			},
 		},
+		{
+			name:           "cluster with schema registry",
+			args:           []string{"config", "set-cluster", "registry-cluster", "--bootstrap-servers", "localhost:9092", "--schema-registry", "http://localhost:8081", "--schema-registry-username", "user", "--schema-registry-password", "password"},
+			testConfig:     test_helpers.TestConfig{},
+			expectedError:  false,
+			expectedOutput: "Setting up cluster 'registry-cluster' with bootstrap servers: [localhost:9092]\nSetting up schema registry: http://localhost:8081\n",
+			verifyConfig: func(t *testing.T) {
+				assert.Equal(t, "http://localhost:8081", viper.GetString("clusters.registry-cluster.schema-registry"))
+				assert.Equal(t, "user", viper.GetString("clusters.registry-cluster.schema-registry-username"))
+				assert.Equal(t, "password", viper.GetString("clusters.registry-cluster.schema-registry-password"))
+			},
+		},
+		{
+			name: "update cluster keeps schema registry",
+			args: []string{"config", "set-cluster", "registry-cluster", "--bootstrap-servers", "new-server:9092"},
+			testConfig: test_helpers.TestConfig{
+				Clusters: map[string]map[string]interface{}{
+					"registry-cluster": {
+						"bootstrap-servers": []string{"old-server:9092"},
+						"schema-registry":   "http://localhost:8081",
+					},
+				},
+			},
+			expectedError:  false,
+			expectedOutput: "Setting up cluster 'registry-cluster' with bootstrap servers: [new-server:9092]\n",
+			verifyConfig: func(t *testing.T) {
+				assert.Equal(t, "http://localhost:8081", viper.GetString("clusters.registry-cluster.schema-registry"))
+			},
+		},
+		{
+			name:           "cluster with protobuf topics",
+			args:           []string{"config", "set-cluster", "proto-cluster", "--bootstrap-servers", "localhost:9092", "--proto-descriptor-set", "/protos/shop.pb", "--proto-topic", "orders=shop.Order", "--proto-topic", "payments=shop.Payment"},
+			testConfig:     test_helpers.TestConfig{},
+			expectedError:  false,
+			expectedOutput: "Setting up cluster 'proto-cluster' with bootstrap servers: [localhost:9092]\nSetting up value decoder of topic 'orders': protobuf:/protos/shop.pb:shop.Order\nSetting up value decoder of topic 'payments': protobuf:/protos/shop.pb:shop.Payment\n",
+			verifyConfig: func(t *testing.T) {
+				topicDecoders, err := cmd.GetClusterTopicDecoders("proto-cluster")
+				assert.NoError(t, err)
+				assert.Equal(t, []cmd.TopicDecoders{
+					{Topic: "orders", Value: "protobuf:/protos/shop.pb:shop.Order"},
+					{Topic: "payments", Value: "protobuf:/protos/shop.pb:shop.Payment"},
+				}, topicDecoders)
+			},
+		},
+		{
+			name: "update and remove protobuf topics",
+			args: []string{"config", "set-cluster", "proto-cluster", "--bootstrap-servers", "localhost:9092", "--proto-descriptor-set", "/protos/v2.pb", "--proto-topic", "orders=shop.v2.Order", "--proto-topic", "payments="},
+			testConfig: test_helpers.TestConfig{
+				Clusters: map[string]map[string]interface{}{
+					"proto-cluster": {
+						"bootstrap-servers": []string{"localhost:9092"},
+						"topic-decoders": []map[string]string{
+							{"topic": "orders", "key": "", "value": "protobuf:/protos/shop.pb:shop.Order"},
+							{"topic": "payments", "key": "text", "value": "protobuf:/protos/shop.pb:shop.Payment"},
+							{"topic": "users", "key": "", "value": "protobuf:/protos/shop.pb:shop.User"},
+						},
+					},
+				},
+			},
+			expectedError:  false,
+			expectedOutput: "Setting up cluster 'proto-cluster' with bootstrap servers: [localhost:9092]\nSetting up value decoder of topic 'orders': protobuf:/protos/v2.pb:shop.v2.Order\nRemoving value decoder of topic 'payments'\n",
+			verifyConfig: func(t *testing.T) {
+				topicDecoders, err := cmd.GetClusterTopicDecoders("proto-cluster")
+				assert.NoError(t, err)
+				assert.Equal(t, []cmd.TopicDecoders{
+					{Topic: "orders", Value: "protobuf:/protos/v2.pb:shop.v2.Order"},
+					{Topic: "payments", Key: "text"},
+					{Topic: "users", Value: "protobuf:/protos/shop.pb:shop.User"},
+				}, topicDecoders)
+			},
+		},
+		{
+			name:           "protobuf topic with a value decoder",
+			args:           []string{"config", "set-cluster", "proto-cluster", "--bootstrap-servers", "localhost:9092", "--proto-descriptor-set", "/protos/shop.pb", "--proto-topic", "orders=shop.Order", "--topic-value-decoder", "orders=json"},
+			testConfig:     test_helpers.TestConfig{},
+			expectedError:  true,
+			expectedOutput: "Error: the value decoder of topic 'orders' is set by both --proto-topic and --topic-value-decoder",
+		},
+		{
+			name:           "protobuf topic without descriptor set",
+			args:           []string{"config", "set-cluster", "proto-cluster", "--bootstrap-servers", "localhost:9092", "--proto-topic", "orders=shop.Order"},
+			testConfig:     test_helpers.TestConfig{},
+			expectedError:  true,
+			expectedOutput: "Error: --proto-descriptor-set is required to set the Protobuf message of topic 'orders'",
+		},
+		{
+			name:           "invalid protobuf topic",
+			args:           []string{"config", "set-cluster", "proto-cluster", "--bootstrap-servers", "localhost:9092", "--proto-topic", "orders"},
+			testConfig:     test_helpers.TestConfig{},
+			expectedError:  true,
+			expectedOutput: "Error: invalid --proto-topic 'orders'. Expected topic=package.Message",
+		},
+		{
+			name:           "cluster with topic decoders",
+			args:           []string{"config", "set-cluster", "decoder-cluster", "--bootstrap-servers", "localhost:9092", "--topic-key-decoder", "orders=text", "--topic-value-decoder", "orders=avro:/schemas/order.avsc", "--topic-value-decoder", "audit=exec:tr a-z A-Z"},
+			testConfig:     test_helpers.TestConfig{},
+			expectedError:  false,
+			expectedOutput: "Setting up cluster 'decoder-cluster' with bootstrap servers: [localhost:9092]\nSetting up key decoder of topic 'orders': text\nSetting up value decoder of topic 'orders': avro:/schemas/order.avsc\nSetting up value decoder of topic 'audit': exec:tr a-z A-Z\n",
+			verifyConfig: func(t *testing.T) {
+				topicDecoders, err := cmd.GetClusterTopicDecoders("decoder-cluster")
+				assert.NoError(t, err)
+				assert.Equal(t, []cmd.TopicDecoders{
+					{Topic: "orders", Key: "text", Value: "avro:/schemas/order.avsc"},
+					{Topic: "audit", Value: "exec:tr a-z A-Z"},
+				}, topicDecoders)
+			},
+		},
+		{
+			name: "update and remove topic decoders",
+			args: []string{"config", "set-cluster", "decoder-cluster", "--bootstrap-servers", "localhost:9092", "--topic-key-decoder", "orders=", "--topic-value-decoder", "audit=", "--topic-value-decoder", "payments=protobuf:/protos/shop.pb:shop.Payment"},
+			testConfig: test_helpers.TestConfig{
+				Clusters: map[string]map[string]interface{}{
+					"decoder-cluster": {
+						"bootstrap-servers": []string{"localhost:9092"},
+						"topic-decoders": []map[string]string{
+							{"topic": "orders", "key": "text", "value": "json"},
+							{"topic": "audit", "key": "", "value": "exec:cat"},
+						},
+					},
+				},
+			},
+			expectedError:  false,
+			expectedOutput: "Setting up cluster 'decoder-cluster' with bootstrap servers: [localhost:9092]\nRemoving key decoder of topic 'orders'\nRemoving value decoder of topic 'audit'\nSetting up value decoder of topic 'payments': protobuf:/protos/shop.pb:shop.Payment\n",
+			verifyConfig: func(t *testing.T) {
+				topicDecoders, err := cmd.GetClusterTopicDecoders("decoder-cluster")
+				assert.NoError(t, err)
+				assert.Equal(t, []cmd.TopicDecoders{
+					{Topic: "orders", Value: "json"},
+					{Topic: "payments", Value: "protobuf:/protos/shop.pb:shop.Payment"},
+				}, topicDecoders)
+			},
+		},
+		{
+			name:           "invalid topic decoder",
+			args:           []string{"config", "set-cluster", "decoder-cluster", "--bootstrap-servers", "localhost:9092", "--topic-value-decoder", "orders=thrift"},
+			testConfig:     test_helpers.TestConfig{},
+			expectedError:  true,
+			expectedOutput: "Error: invalid --topic-value-decoder 'orders=thrift': unknown decoder. Use one of: " + cmd.DeserializersUsage,
+		},
+		{
+			name:           "topic decoder without topic",
+			args:           []string{"config", "set-cluster", "decoder-cluster", "--bootstrap-servers", "localhost:9092", "--topic-key-decoder", "text"},
+			testConfig:     test_helpers.TestConfig{},
+			expectedError:  true,
+			expectedOutput: "Error: invalid --topic-key-decoder 'text'. Expected topic=decoder",
+		},
 		{
 			name:                "no args shows help",
 			args:                []string{"config", "set-cluster"},
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// SensitiveMask is displayed in place of the values of sensitive configs, such as passwords
+const SensitiveMask = "******"
+
+// FormatConfigValue renders the value of a config of a topic or broker, masking sensitive values
+func FormatConfigValue(config kadm.Config) string {
+	if config.Sensitive {
+		return SensitiveMask
+	}
+	if config.Value == nil {
+		return "-"
+	}
+	return *config.Value
+}
+
+// ConfigSourceName returns where the value of a config comes from: the default of Kafka, the static configuration of
+// the broker, or a config set dynamically on a topic, on a broker or as the default of all brokers
+func ConfigSourceName(source kmsg.ConfigSource) string {
+	switch source {
+	case kmsg.ConfigSourceDefaultConfig:
+		return "default"
+	case kmsg.ConfigSourceStaticBrokerConfig:
+		return "static"
+	case kmsg.ConfigSourceDynamicTopicConfig:
+		return "dynamic-topic"
+	case kmsg.ConfigSourceDynamicBrokerConfig:
+		return "dynamic-broker"
+	case kmsg.ConfigSourceDynamicDefaultBrokerConfig:
+		return "dynamic-default"
+	case kmsg.ConfigSourceDynamicBrokerLoggerConfig:
+		return "dynamic-broker-logger"
+	}
+	return "unknown"
+}
+
+// AlterConfigsError returns the first error of the responses to altering configs, if any
+func AlterConfigsError(responses kadm.AlterConfigsResponses) error {
+	for _, response := range responses {
+		if response.Err == nil {
+			continue
+		}
+		if response.ErrMessage != "" {
+			return fmt.Errorf("%v: %s", response.Err, response.ErrMessage)
+		}
+		return response.Err
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestFormatConfigValue(t *testing.T) {
+	value := "604800000"
+	assert.Equal(t, "604800000", FormatConfigValue(kadm.Config{Key: "retention.ms", Value: &value}))
+	assert.Equal(t, "-", FormatConfigValue(kadm.Config{Key: "log.dirs"}))
+	password := "secret"
+	assert.Equal(t, SensitiveMask, FormatConfigValue(kadm.Config{Key: "ssl.key.password", Value: &password, Sensitive: true}))
+	assert.Equal(t, SensitiveMask, FormatConfigValue(kadm.Config{Key: "ssl.key.password", Sensitive: true}))
+}
+
+func TestConfigSourceName(t *testing.T) {
+	assert.Equal(t, "default", ConfigSourceName(kmsg.ConfigSourceDefaultConfig))
+	assert.Equal(t, "static", ConfigSourceName(kmsg.ConfigSourceStaticBrokerConfig))
+	assert.Equal(t, "dynamic-topic", ConfigSourceName(kmsg.ConfigSourceDynamicTopicConfig))
+	assert.Equal(t, "dynamic-broker", ConfigSourceName(kmsg.ConfigSourceDynamicBrokerConfig))
+	assert.Equal(t, "dynamic-default", ConfigSourceName(kmsg.ConfigSourceDynamicDefaultBrokerConfig))
+	assert.Equal(t, "unknown", ConfigSourceName(kmsg.ConfigSourceUnknown))
+}
+
+func TestAlterConfigsError(t *testing.T) {
+	assert.NoError(t, AlterConfigsError(kadm.AlterConfigsResponses{{Name: "orders"}}))
+	assert.EqualError(t, AlterConfigsError(kadm.AlterConfigsResponses{
+		{Name: "orders"},
+		{Name: "payments", Err: kerr.InvalidConfig, ErrMessage: "invalid value"},
+		{Name: "users", Err: kerr.UnknownTopicOrPartition},
+	}), "INVALID_CONFIG: Configuration is invalid.: invalid value")
+	assert.ErrorIs(t, AlterConfigsError(kadm.AlterConfigsResponses{{Name: "users", Err: kerr.UnknownTopicOrPartition}}), kerr.UnknownTopicOrPartition)
+}
@@ -2,123 +2,449 @@ package consume
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"github.com/Vidalee/kacao/cmd"
 	"github.com/spf13/cobra"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"maps"
 	"os"
 	"os/signal"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 )
 
 var consumeCmd = &cobra.Command{
-	Use:   "consume <topic_name> [--offset <offset>] [--timeout <seconds>]",
+	Use:   "consume <topic_name>... [--regex] [--offset <offset>] [--timeout <duration>] [--no-group] [--max-messages <count>] [--until-offset <offset>] [--until-time <timestamp>] [--exit-at-end] [--isolation <level>] [--show-control-records]",
 	Short: "Consume messages from a topic",
-	Long:  `Consume messages from a topic with an optional timeout.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Consume messages from one or many topics with an optional timeout.
+
+Several topics can be consumed at once. With --regex, the arguments are regular expressions matched against
+topic names, for example to consume every topic starting with "orders." (use ^ and $ to anchor them):
+- kacao consume 'orders\..*' --regex --format '{{.Topic}}: {{.Value}}'
+
+Consuming stops on the first of the following conditions:
+- --timeout is reached, either a duration (30s, 5m) or a number of seconds
+- --max-messages messages were printed
+- every partition reached --until-offset, or --until-time went by. Messages past these are not printed
+- every partition reached its end offset as it was when the command started, with --exit-at-end:
+  kacao consume <topic_name> --offset earliest --exit-at-end
+
+By default, messages are consumed with the consumer group of the current context, and offsets are committed.
+Use --no-group to read the partitions directly, without joining the consumer group nor committing anything.
+In that mode, --offset accepts earliest, latest (the default), an exact offset, or a RFC3339 timestamp:
+- kacao consume <topic_name> --no-group --offset 42
+- kacao consume <topic_name> --no-group --offset 2025-01-01T00:00:00Z
+
+By default, only the value of each message is printed. Use --format to print other fields:
+- json and jsonl print every field of the message, indented or one message per line. The jsonl output can be produced back with 'kacao produce --from-file':
+  it ignores the decoders and keeps the raw keys and values, with base64 for anything that is not printable
+- any other value is a Go template, executed for each message:
+  kacao consume <topic_name> --format '{{.Partition}}:{{.Offset}} {{.Key}} => {{.Value}}'
+  Available fields are Topic, Partition, Offset, Timestamp, Key, Value and Headers (a list of Key and Value),
+  as well as ProducerID for transactional messages and Control (ABORT or COMMIT) for transaction markers.
+
+Messages of transactions are read with --isolation read_uncommitted by default, like they are written. Messages of
+aborted transactions are then printed like committed ones: they are only told apart by the ABORT marker which follows
+them, with the same producer id. Use --isolation read_committed to see what a transactional consumer would: messages of
+aborted transactions are hidden, and consuming stops before ongoing transactions (--exit-at-end stops at the last
+stable offset). --show-control-records also prints the commit and abort markers of transactions, with the id of their
+producer:
+- kacao consume <topic_name> --offset earliest --exit-at-end --show-control-records --format '{{.Offset}} {{.ProducerID}} {{.Control}} {{.Value}}'
+
+Binary keys, values and header values can be displayed with --key-encoding, --value-encoding and --header-encoding:
+text (the default), hex, base64 or auto, which uses text for printable data and base64 otherwise.
+
+Keys and values serialized with a schema registry (Avro, Protobuf or JSON Schema, in the Confluent wire format) are
+decoded to JSON with --key-decoder schema-registry and --value-decoder schema-registry. The schema registry is set on
+the cluster with 'kacao config set-cluster <name> --bootstrap-servers <servers> --schema-registry <url>'. Data which is
+not in the wire format is displayed with the encoding flags.
+
+Raw Protobuf values are decoded to JSON without a schema registry with --proto-descriptor-set and --proto-message,
+the descriptor set being written by 'protoc --include_imports --descriptor_set_out=shop.pb shop.proto'. Topics can
+also be mapped to their message in the cluster configuration, to be decoded without flags:
+- kacao config set-cluster <name> --bootstrap-servers <servers> --proto-descriptor-set shop.pb --proto-topic orders=shop.Order
+
+Other decoders are selected with --key-decoder and --value-decoder: text, json (compacted), avro:<schema.avsc> for raw
+Avro data, protobuf:<descriptor_set.pb>:<message> for raw Protobuf data, and exec:<command>, which runs a shell command
+with the data on its standard input and displays its standard output. The command is started once per key and per
+value, and stopped after 10 seconds, so it suits small numbers of messages. The decoders of topics are set in the
+cluster configuration, and used when the flags are not set:
+- kacao config set-cluster <name> --bootstrap-servers <servers> --topic-value-decoder orders=avro:order.avsc --topic-key-decoder audit=exec:audit-decode`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(command *cobra.Command, args []string) error {
 		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
 		cobra.CheckErr(err)
-		consumerGroup, err := cmd.GetConsumerGroup()
-		cobra.CheckErr(err)
 		offsetArg, err := command.Flags().GetString("offset")
 		cobra.CheckErr(err)
 		timeoutArg, err := command.Flags().GetString("timeout")
 		cobra.CheckErr(err)
+		noGroup, err := command.Flags().GetBool("no-group")
+		cobra.CheckErr(err)
+		formatArg, err := command.Flags().GetString("format")
+		cobra.CheckErr(err)
+
+		encodings, err := cmd.GetEncodingFlags(command)
+		if err != nil {
+			return err
+		}
+		formatRecord, err := cmd.NewRecordFormatter(formatArg, encodings)
+		if err != nil {
+			return err
+		}
+		isolation, err := cmd.GetIsolationFlags(command)
+		if err != nil {
+			return err
+		}
 
-		if offsetArg != "" && offsetArg != "earliest" && offsetArg != "latest" {
+		timeoutDuration, err := parseTimeout(timeoutArg)
+		if err != nil {
+			return err
+		}
+		maxMessages, err := command.Flags().GetInt("max-messages")
+		cobra.CheckErr(err)
+		untilOffset, err := command.Flags().GetInt64("until-offset")
+		cobra.CheckErr(err)
+		untilTimeArg, err := command.Flags().GetString("until-time")
+		cobra.CheckErr(err)
+		exitAtEnd, err := command.Flags().GetBool("exit-at-end")
+		cobra.CheckErr(err)
+		useRegex, err := command.Flags().GetBool("regex")
+		cobra.CheckErr(err)
+
+		var topicPatterns []*regexp.Regexp
+		if useRegex {
+			for _, arg := range args {
+				pattern, err := regexp.Compile(arg)
+				if err != nil {
+					return fmt.Errorf("invalid topic regular expression '%s': %v", arg, err)
+				}
+				topicPatterns = append(topicPatterns, pattern)
+			}
+		}
+
+		if noGroup {
+			if err := validateStartOffset(offsetArg); err != nil {
+				return err
+			}
+		} else if offsetArg != "" && offsetArg != "earliest" && offsetArg != "latest" {
 			return fmt.Errorf("invalid offset argument. Use 'earliest' or 'latest'. By default, the last committed offset by Kacao will be used")
 		}
+		if maxMessages < 0 {
+			return fmt.Errorf("invalid max messages value. Must be a non-negative integer")
+		}
+		var untilTime time.Time
+		if untilTimeArg != "" {
+			untilTime, err = time.Parse(time.RFC3339, untilTimeArg)
+			if err != nil {
+				return fmt.Errorf("invalid until time value. Must be a RFC3339 timestamp, for example 2025-01-01T00:00:00Z")
+			}
+		}
+		// Messages produced from now on are after an --until-time which went by, consuming stops at the end offsets
+		untilTimePassed := !untilTime.IsZero() && !untilTime.After(time.Now())
+		exitAtEnd = exitAtEnd || untilTimePassed
+		trackOffsets := exitAtEnd || untilOffset >= 0
 
-		timeoutDuration := time.Duration(0)
-		if timeoutArg != "" {
-			seconds, err := strconv.Atoi(timeoutArg)
-			if err != nil || seconds < 0 {
-				return fmt.Errorf("invalid timeout value. Must be a non-negative integer representing seconds")
+		var consumerGroup string
+		opts := append([]kgo.Opt{kgo.SeedBrokers(boostrapServers...)}, isolation.ClientOpts()...)
+		if !noGroup {
+			consumerGroup, err = cmd.GetConsumerGroup()
+			cobra.CheckErr(err)
+			opts = append(opts, kgo.ConsumerGroup(consumerGroup), kgo.ConsumeTopics(args...))
+			if useRegex {
+				opts = append(opts, kgo.ConsumeRegex())
 			}
-			timeoutDuration = time.Duration(seconds) * time.Second
+			// Offsets of the printed messages are committed once, when consume stops
+			opts = append(opts, kgo.DisableAutoCommit())
+		}
+		if trackOffsets {
+			// Control records take up offsets, they are needed to know when a partition reached its target offset
+			opts = append(opts, kgo.KeepControlRecords())
 		}
 
-		cl, err := kgo.NewClient(
-			kgo.SeedBrokers(boostrapServers...),
-			kgo.ConsumerGroup(consumerGroup),
-			kgo.ConsumeTopics(args[0]),
-		)
+		cl, err := kgo.NewClient(opts...)
 		cobra.CheckErr(err)
 		adminClient := kadm.NewClient(cl)
 		defer cl.Close()
 		defer adminClient.Close()
 
-		ctx := context.Background()
+		signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stopSignals()
+		timeoutCtx := signalCtx
 		if timeoutDuration > 0 {
 			var cancel context.CancelFunc
-			ctx, cancel = context.WithTimeout(ctx, timeoutDuration)
+			timeoutCtx, cancel = context.WithTimeout(timeoutCtx, timeoutDuration)
+			defer cancel()
+		}
+		ctx := timeoutCtx
+		if !untilTime.IsZero() && !untilTimePassed {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, untilTime)
 			defer cancel()
 		}
 
-		committedListedOffsets, _ := adminClient.ListEndOffsets(ctx, args...)
+		topics := args
+		if useRegex {
+			topics, err = matchingTopics(ctx, adminClient, topicPatterns)
+			if err != nil {
+				return err
+			}
+			if len(topics) == 0 {
+				return fmt.Errorf("no topic matches the regular expression(s) '%s'", strings.Join(args, "', '"))
+			}
+		}
+
+		committedListedOffsets, err := isolation.ListEndOffsets(ctx, adminClient, topics...)
+		if err != nil {
+			return fmt.Errorf("error listing offsets: %v\n", err)
+		}
+		var startOffsets map[string]map[int32]int64
 
-		if offsetArg != "" {
-			var newOffsets kadm.Offsets = make(map[string]map[int32]kadm.Offset)
-			newOffsets[args[0]] = make(map[int32]kadm.Offset)
-			for _, listedOffset := range committedListedOffsets[args[0]] {
-				var offsetValue int64 = 0
-				if offsetArg == "latest" {
-					offsetValue = listedOffset.Offset
+		if noGroup {
+			startOffsets, err = resolveStartOffsets(ctx, adminClient, offsetArg, committedListedOffsets)
+			if err != nil {
+				return err
+			}
+			partitions := make(map[string]map[int32]kgo.Offset)
+			for topic, offsets := range startOffsets {
+				partitions[topic] = make(map[int32]kgo.Offset)
+				for partition, offset := range offsets {
+					partitions[topic][partition] = kgo.NewOffset().At(offset)
 				}
-				newOffsets[args[0]][listedOffset.Partition] = kadm.Offset{
-					Topic:       listedOffset.Topic,
-					Partition:   listedOffset.Partition,
-					At:          offsetValue,
-					LeaderEpoch: listedOffset.LeaderEpoch,
-					Metadata:    "",
+			}
+			cl.AddConsumePartitions(partitions)
+		} else {
+			if offsetArg != "" {
+				var newOffsets kadm.Offsets = make(map[string]map[int32]kadm.Offset)
+				committedListedOffsets.Each(func(listedOffset kadm.ListedOffset) {
+					if listedOffset.Err != nil {
+						return
+					}
+					var offsetValue int64 = 0
+					if offsetArg == "latest" {
+						offsetValue = listedOffset.Offset
+					}
+					newOffsets.Add(kadm.Offset{
+						Topic:       listedOffset.Topic,
+						Partition:   listedOffset.Partition,
+						At:          offsetValue,
+						LeaderEpoch: listedOffset.LeaderEpoch,
+						Metadata:    "",
+					})
+				})
+				err := adminClient.CommitAllOffsets(ctx, consumerGroup, newOffsets)
+				cobra.CheckErr(err)
+			}
+			if trackOffsets {
+				startOffsets, err = groupStartOffsets(ctx, adminClient, consumerGroup, committedListedOffsets)
+				if err != nil {
+					return err
 				}
 			}
-			err := adminClient.CommitAllOffsets(ctx, consumerGroup, newOffsets)
-			cobra.CheckErr(err)
 		}
 
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-		go func() {
-			<-sigChan
-			fmt.Println("Closing client...")
-			cl.Close()
-			adminClient.Close()
-		}()
+		stop := newStopConditions(maxMessages, untilOffset, untilTime, exitAtEnd, startOffsets, listedOffsetsMap(committedListedOffsets))
+		stop.showControlRecords = isolation.ShowControlRecords
+		if stop.done() {
+			return nil
+		}
 
-		for {
-			select {
-			case <-ctx.Done():
-				_, err := fmt.Fprintln(command.OutOrStdout(), "Timeout reached. Stopping consumer.")
-				cobra.CheckErr(err)
-				return nil
-			default:
-				fetches := cl.PollFetches(ctx)
-				if errs := fetches.Errors(); len(errs) > 0 {
-					if errors.Is(errs[0].Err, context.DeadlineExceeded) {
-						return nil
-					}
-					panic(fmt.Sprint(errs))
+		lastRecords := make(map[string]map[int32]*kgo.Record)
+		// An error formatting a record stops consuming, the offsets of the records printed before are still committed
+		var formatErr error
+		err = cmd.PollRecords(ctx, cl, command.ErrOrStderr(), func(record *kgo.Record) bool {
+			if stop.accept(record) {
+				if err := formatRecord(command.OutOrStdout(), record); err != nil {
+					formatErr = fmt.Errorf("error formatting message at offset %d of partition %d of topic '%s': %v", record.Offset, record.Partition, record.Topic, err)
+					return false
+				}
+				if lastRecords[record.Topic] == nil {
+					lastRecords[record.Topic] = make(map[int32]*kgo.Record)
 				}
+				lastRecords[record.Topic][record.Partition] = record
+			}
+			return !stop.done()
+		})
 
-				iter := fetches.RecordIter()
-				for !iter.Done() {
-					record := iter.Next()
-					_, err := fmt.Fprintln(command.OutOrStdout(), string(record.Value))
-					cobra.CheckErr(err)
+		if !noGroup {
+			var records []*kgo.Record
+			for _, partitions := range lastRecords {
+				for _, record := range partitions {
+					records = append(records, record)
+				}
+			}
+			if len(records) > 0 {
+				commitCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				if commitErr := cl.CommitRecords(commitCtx, records...); commitErr != nil {
+					return fmt.Errorf("error committing offsets of consumer group '%s': %v", consumerGroup, commitErr)
 				}
 			}
 		}
+
+		if formatErr != nil {
+			return formatErr
+		}
+		switch {
+		case signalCtx.Err() != nil:
+			return cmd.ErrInterrupted
+		case timeoutCtx.Err() != nil:
+			_, err := fmt.Fprintln(command.ErrOrStderr(), "Timeout reached. Stopping consumer.")
+			return err
+		case ctx.Err() != nil:
+			return nil
+		}
+		return err
 	},
 }
 
+// matchingTopics returns the topics of the cluster matching any of the patterns, as kgo.ConsumeRegex would
+func matchingTopics(ctx context.Context, adminClient *kadm.Client, patterns []*regexp.Regexp) ([]string, error) {
+	topicDetails, err := adminClient.ListTopics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing topics: %v\n", err)
+	}
+	var topics []string
+	for _, topic := range topicDetails.Names() {
+		for _, pattern := range patterns {
+			if pattern.MatchString(topic) {
+				topics = append(topics, topic)
+				break
+			}
+		}
+	}
+	return topics, nil
+}
+
+// parseTimeout accepts a Go duration such as 30s or 5m, or a number of seconds
+func parseTimeout(timeoutArg string) (time.Duration, error) {
+	if timeoutArg == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(timeoutArg)
+	if err != nil {
+		seconds, atoiErr := strconv.Atoi(timeoutArg)
+		err = atoiErr
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid timeout value. Must be a non-negative duration such as 30s or 5m, or a number of seconds")
+	}
+	return timeout, nil
+}
+
+func validateStartOffset(offsetArg string) error {
+	switch offsetArg {
+	case "", "latest", "earliest":
+		return nil
+	}
+	if offset, err := strconv.ParseInt(offsetArg, 10, 64); err == nil {
+		if offset < 0 {
+			return fmt.Errorf("invalid offset argument. An exact offset must be a non-negative integer")
+		}
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, offsetArg); err == nil {
+		return nil
+	}
+	return fmt.Errorf("invalid offset argument. Use 'earliest', 'latest', an exact offset or a RFC3339 timestamp")
+}
+
+// resolveStartOffsets returns the exact offset to start consuming from in each partition, for --no-group
+func resolveStartOffsets(ctx context.Context, adminClient *kadm.Client, offsetArg string, endOffsets kadm.ListedOffsets) (map[string]map[int32]int64, error) {
+	if err := endOffsets.Error(); err != nil {
+		return nil, fmt.Errorf("error listing offsets: %v\n", err)
+	}
+	topics := slices.Collect(maps.Keys(endOffsets))
+
+	listedOffsets := endOffsets
+	var err error
+	switch offsetArg {
+	case "", "latest":
+	case "earliest":
+		listedOffsets, err = adminClient.ListStartOffsets(ctx, topics...)
+	default:
+		if offset, parseErr := strconv.ParseInt(offsetArg, 10, 64); parseErr == nil {
+			startOffsets := listedOffsetsMap(endOffsets)
+			for _, partitions := range startOffsets {
+				for partition := range partitions {
+					partitions[partition] = offset
+				}
+			}
+			return startOffsets, nil
+		}
+		timestamp, parseErr := time.Parse(time.RFC3339, offsetArg)
+		cobra.CheckErr(parseErr)
+		listedOffsets, err = adminClient.ListOffsetsAfterMilli(ctx, timestamp.UnixMilli(), topics...)
+	}
+	if err == nil {
+		err = listedOffsets.Error()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing offsets: %v\n", err)
+	}
+	return listedOffsetsMap(listedOffsets), nil
+}
+
+// groupStartOffsets returns the offsets the consumer group will start from, which are the committed offsets, or the
+// start of the partitions when nothing was committed yet
+func groupStartOffsets(ctx context.Context, adminClient *kadm.Client, consumerGroup string, endOffsets kadm.ListedOffsets) (map[string]map[int32]int64, error) {
+	committedOffsets, err := adminClient.FetchOffsets(ctx, consumerGroup)
+	if err == nil {
+		err = committedOffsets.Error()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching committed offsets of consumer group '%s': %v\n", consumerGroup, err)
+	}
+	listedStartOffsets, err := adminClient.ListStartOffsets(ctx, slices.Collect(maps.Keys(endOffsets))...)
+	if err == nil {
+		err = listedStartOffsets.Error()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing offsets: %v\n", err)
+	}
+
+	startOffsets := listedOffsetsMap(listedStartOffsets)
+	for topic, partitions := range startOffsets {
+		for partition, startOffset := range partitions {
+			committedOffset, ok := committedOffsets.Lookup(topic, partition)
+			if ok && committedOffset.At > startOffset {
+				partitions[partition] = committedOffset.At
+			}
+		}
+	}
+	return startOffsets, nil
+}
+
+func listedOffsetsMap(listedOffsets kadm.ListedOffsets) map[string]map[int32]int64 {
+	offsets := make(map[string]map[int32]int64)
+	listedOffsets.Each(func(listedOffset kadm.ListedOffset) {
+		if listedOffset.Err != nil {
+			return
+		}
+		if offsets[listedOffset.Topic] == nil {
+			offsets[listedOffset.Topic] = make(map[int32]int64)
+		}
+		offsets[listedOffset.Topic][listedOffset.Partition] = listedOffset.Offset
+	})
+	return offsets
+}
+
 func init() {
-	consumeCmd.Flags().StringP("offset", "o", "", "Offset to start consuming from (latest, earliest, or by default the last committed offset by Kacao)")
-	consumeCmd.Flags().StringP("timeout", "t", "", "Timeout in seconds to stop consuming messages")
+	consumeCmd.Flags().StringP("offset", "o", "", "Offset to start consuming from (latest, earliest, or by default the last committed offset by Kacao). With --no-group, an exact offset or a RFC3339 timestamp are also accepted")
+	consumeCmd.Flags().StringP("timeout", "t", "", "Timeout to stop consuming messages, as a duration (30s, 5m) or a number of seconds")
+	consumeCmd.Flags().Int("max-messages", 0, "Stop after printing this number of messages")
+	consumeCmd.Flags().Int64("until-offset", -1, "Stop consuming each partition once this offset is reached")
+	consumeCmd.Flags().String("until-time", "", "Stop consuming messages timestamped after this RFC3339 timestamp, and stop once it is reached")
+	consumeCmd.Flags().Bool("exit-at-end", false, "Stop once the end offsets of the partitions at the start of the command are reached")
+	consumeCmd.Flags().StringP("format", "f", "", "Output format of the messages: json, jsonl or a Go template. By default only the value is printed")
+	consumeCmd.Flags().Bool("regex", false, "Treat the arguments as regular expressions matching the topics to consume")
+	consumeCmd.Flags().Bool("no-group", false, "Consume without joining the consumer group of the current context and without committing offsets")
+	cmd.AddEncodingFlags(consumeCmd)
+	cmd.AddIsolationFlags(consumeCmd)
 	cmd.RootCmd.AddCommand(consumeCmd)
 }
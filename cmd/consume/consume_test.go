@@ -9,12 +9,19 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/kafka"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+	"google.golang.org/protobuf/encoding/protowire"
+	"os"
+	"path/filepath"
 	"regexp"
 	"sync"
 	"testing"
 	"time"
 )
 
+const userSchema = `{"type": "record", "name": "User", "fields": [{"name": "name", "type": "string"}, {"name": "age", "type": "int"}]}`
+
 func TestConsumeMessages(t *testing.T) {
 	ctx := context.Background()
 
@@ -44,10 +51,30 @@ func TestConsumeMessages(t *testing.T) {
 	defer cl.Close()
 	defer adminClient.Close()
 
+	registry := srfake.New()
+	defer registry.Close()
+	registry.SeedSchema("consume-topic-avro-value", 1, 1, sr.Schema{Schema: userSchema})
+	registry.SeedSchema("consume-topic-protobuf-value", 1, 2, sr.Schema{Schema: `syntax = "proto3"; message User { string name = 1; int32 age = 2; }`, Type: sr.TypeProtobuf})
+	registry.SeedSchema("consume-topic-json-schema-value", 1, 3, sr.Schema{Schema: `{"type": "object"}`, Type: sr.TypeJSON})
+	userProtobuf := protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), "bob")
+	userProtobuf = protowire.AppendVarint(protowire.AppendTag(userProtobuf, 2, protowire.VarintType), 25)
+
+	userDescriptorSet := test_helpers.ProtoDescriptorSet(t, `syntax = "proto3"; package shop; message User { string name = 1; int32 age = 2; }`)
+	userSchemaFile := filepath.Join(t.TempDir(), "user.avsc")
+	assert.NoError(t, os.WriteFile(userSchemaFile, []byte(userSchema), 0644))
+	// Raw Avro data, without the magic byte and schema id of the wire format
+	userAvro := test_helpers.AvroWireFormat(t, 1, userSchema, map[string]any{"name": "alice", "age": 30})[5:]
+
 	testConfig := test_helpers.TestConfig{
 		Clusters: map[string]map[string]interface{}{
 			"test-cluster": {
 				"bootstrap-servers": brokers,
+				"schema-registry":   registry.URL(),
+				"topic-decoders": []map[string]string{
+					{"topic": "consume-topic-protobuf-config", "value": "protobuf:" + userDescriptorSet + ":shop.User"},
+					{"topic": "consume-topic-decoders-config", "key": "exec:tr a-z A-Z", "value": "json"},
+					{"topic": "consume-topic-decoders-override", "key": "exec:tr a-z A-Z", "value": "json"},
+				},
 			},
 		},
 		Contexts: map[string]map[string]interface{}{
@@ -61,11 +88,18 @@ func TestConsumeMessages(t *testing.T) {
 	tests := []struct {
 		name                  string
 		topicName             string
+		extraTopics           []string
 		produceMessages       []string
+		produceRecords        []*kgo.Record
 		getArgs               []string
 		expectedPatterns      []*regexp.Regexp
+		unexpectedPatterns    []*regexp.Regexp
 		expectedError         bool
 		produceAfterConsuming bool
+		expectNoCommit        bool
+		expectedCommitAt      int64
+		abortedTransaction    []string
+		committedTransaction  []string
 	}{
 		{
 			name:            "consume messages earliest offset",
@@ -93,7 +127,425 @@ func TestConsumeMessages(t *testing.T) {
 			getArgs:       []string{"consume", "consume-topic-negative-timeout", "--offset", "latest", "--timeout", "-1"},
 			expectedError: true,
 			expectedPatterns: []*regexp.Regexp{
-				regexp.MustCompile(`Error: invalid timeout value. Must be a non-negative integer representing seconds`),
+				regexp.MustCompile(`Error: invalid timeout value. Must be a non-negative duration such as 30s or 5m, or a number of seconds`),
+			},
+		},
+		{
+			name:            "consume messages with duration timeout",
+			topicName:       "consume-topic-duration-timeout",
+			produceMessages: []string{"Duration 1"},
+			getArgs:         []string{"consume", "consume-topic-duration-timeout", "--offset", "earliest", "--timeout", "3s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Duration 1`),
+			},
+		},
+		{
+			name:            "consume messages until the end",
+			topicName:       "consume-topic-exit-at-end",
+			produceMessages: []string{"End 1", "End 2", "End 3"},
+			getArgs:         []string{"consume", "consume-topic-exit-at-end", "--offset", "earliest", "--exit-at-end", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^End 1\nEnd 2\nEnd 3\n$`),
+			},
+		},
+		{
+			name:            "consume messages until the end without group",
+			topicName:       "consume-topic-exit-at-end-no-group",
+			produceMessages: []string{"End 1", "End 2"},
+			getArgs:         []string{"consume", "consume-topic-exit-at-end-no-group", "--no-group", "--offset", "earliest", "--exit-at-end", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^End 1\nEnd 2\n$`),
+			},
+			expectNoCommit: true,
+		},
+		{
+			name:      "consume empty topic until the end",
+			topicName: "consume-topic-exit-at-end-empty",
+			getArgs:   []string{"consume", "consume-topic-exit-at-end-empty", "--offset", "earliest", "--exit-at-end", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^$`),
+			},
+		},
+		{
+			name:            "consume max messages",
+			topicName:       "consume-topic-max-messages",
+			produceMessages: []string{"Max 1", "Max 2", "Max 3"},
+			getArgs:         []string{"consume", "consume-topic-max-messages", "--offset", "earliest", "--max-messages", "2", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Max 1\nMax 2\n$`),
+			},
+		},
+		{
+			name:            "consume until offset",
+			topicName:       "consume-topic-until-offset",
+			produceMessages: []string{"Until 1", "Until 2", "Until 3"},
+			getArgs:         []string{"consume", "consume-topic-until-offset", "--no-group", "--offset", "earliest", "--until-offset", "1", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Until 1\n$`),
+			},
+		},
+		{
+			name:      "consume until a past time",
+			topicName: "consume-topic-until-past-time",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-until-past-time", Value: []byte("Before"), Timestamp: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+				{Topic: "consume-topic-until-past-time", Value: []byte("After")},
+			},
+			getArgs: []string{"consume", "consume-topic-until-past-time", "--offset", "earliest", "--until-time", "2021-01-01T00:00:00Z", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Before\n$`),
+			},
+		},
+		{
+			name:            "consume stops at a decoding error",
+			topicName:       "consume-topic-decoding-error",
+			produceMessages: []string{"Good", "bad"},
+			getArgs:         []string{"consume", "consume-topic-decoding-error", "--offset", "earliest", "--timeout", "30s", "--value-decoder", "exec:grep -v bad"},
+			expectedError:   true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Good\n`),
+				regexp.MustCompile(`Error: error formatting message at offset 1 of partition 0 of topic 'consume-topic-decoding-error': error decoding value: error running 'grep -v bad'`),
+			},
+			// The offset of the printed message is committed
+			expectedCommitAt: 1,
+		},
+		{
+			name:          "consume with invalid until time",
+			topicName:     "consume-topic-invalid-until-time",
+			getArgs:       []string{"consume", "consume-topic-invalid-until-time", "--until-time", "tomorrow"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid until time value. Must be a RFC3339 timestamp`),
+			},
+		},
+		{
+			name:        "consume multiple topics",
+			topicName:   "consume-topic-multi-a",
+			extraTopics: []string{"consume-topic-multi-b"},
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-multi-a", Value: []byte("Multi A")},
+				{Topic: "consume-topic-multi-b", Value: []byte("Multi B")},
+			},
+			getArgs: []string{"consume", "consume-topic-multi-a", "consume-topic-multi-b", "--offset", "earliest", "--exit-at-end", "--timeout", "30s", "--format", "{{.Topic}}: {{.Value}}"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`consume-topic-multi-a: Multi A\n`),
+				regexp.MustCompile(`consume-topic-multi-b: Multi B\n`),
+			},
+		},
+		{
+			name:        "consume topics matching a regular expression",
+			topicName:   "consume-regex-orders.eu",
+			extraTopics: []string{"consume-regex-orders.us", "consume-regex-other"},
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-regex-orders.eu", Value: []byte("Order EU")},
+				{Topic: "consume-regex-orders.us", Value: []byte("Order US")},
+				{Topic: "consume-regex-other", Value: []byte("Other")},
+			},
+			getArgs: []string{"consume", `^consume-regex-orders\..*`, "--regex", "--no-group", "--offset", "earliest", "--exit-at-end", "--timeout", "30s", "--format", "{{.Topic}}: {{.Value}}"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`consume-regex-orders.eu: Order EU\n`),
+				regexp.MustCompile(`consume-regex-orders.us: Order US\n`),
+			},
+			unexpectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Other`),
+			},
+			expectNoCommit: true,
+		},
+		{
+			name:          "consume with invalid regular expression",
+			topicName:     "consume-regex-invalid",
+			getArgs:       []string{"consume", "consume-regex-(", "--regex"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid topic regular expression 'consume-regex-\('`),
+			},
+		},
+		{
+			name:          "consume with regular expression matching no topic",
+			topicName:     "consume-regex-no-match",
+			getArgs:       []string{"consume", "^does-not-exist$", "--regex", "--timeout", "5s"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: no topic matches the regular expression\(s\) '\^does-not-exist\$'`),
+			},
+		},
+		{
+			name:      "consume tombstone and empty message",
+			topicName: "consume-topic-tombstone",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-tombstone", Key: []byte("key"), Value: []byte("")},
+				{Topic: "consume-topic-tombstone", Key: []byte("key"), Value: nil},
+			},
+			getArgs: []string{"consume", "consume-topic-tombstone", "--offset", "earliest", "--timeout", "5"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`\n<null>\n`),
+			},
+		},
+		{
+			name:            "consume without group from exact offset",
+			topicName:       "consume-topic-no-group-offset",
+			produceMessages: []string{"No Group 1", "No Group 2", "No Group 3"},
+			getArgs:         []string{"consume", "consume-topic-no-group-offset", "--no-group", "--offset", "1", "--timeout", "5"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`No Group 2\s+No Group 3`),
+			},
+			unexpectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`No Group 1`),
+			},
+			expectNoCommit: true,
+		},
+		{
+			name:            "consume without group from timestamp",
+			topicName:       "consume-topic-no-group-timestamp",
+			produceMessages: []string{"Timestamp 1", "Timestamp 2"},
+			getArgs:         []string{"consume", "consume-topic-no-group-timestamp", "--no-group", "--offset", "2000-01-01T00:00:00Z", "--timeout", "5"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Timestamp 1\s+Timestamp 2`),
+			},
+			expectNoCommit: true,
+		},
+		{
+			name:            "consume without group latest offset with delayed produce",
+			topicName:       "consume-topic-no-group-latest",
+			produceMessages: []string{"Delayed No Group 1", "Delayed No Group 2"},
+			getArgs:         []string{"consume", "consume-topic-no-group-latest", "--no-group", "--timeout", "10"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Delayed No Group 1\s+Delayed No Group 2`),
+			},
+			produceAfterConsuming: true,
+			expectNoCommit:        true,
+		},
+		{
+			name:      "consume messages with template format",
+			topicName: "consume-topic-template",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-template", Key: []byte("key-1"), Value: []byte("Template 1")},
+				{Topic: "consume-topic-template", Key: nil, Value: []byte("Template 2")},
+			},
+			getArgs: []string{"consume", "consume-topic-template", "--offset", "earliest", "--timeout", "5", "--format", "{{.Topic}} {{.Partition}}:{{.Offset}} {{.Key}} => {{.Value}}"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`consume-topic-template 0:0 key-1 => Template 1\nconsume-topic-template 0:1 <null> => Template 2\n`),
+			},
+		},
+		{
+			name:      "consume messages with jsonl format",
+			topicName: "consume-topic-jsonl",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-jsonl", Key: []byte("key-1"), Value: nil, Headers: []kgo.RecordHeader{{Key: "header-key", Value: []byte("header-value")}}},
+			},
+			getArgs: []string{"consume", "consume-topic-jsonl", "--offset", "earliest", "--timeout", "5", "--format", "jsonl"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`\{"topic":"consume-topic-jsonl","partition":0,"offset":0,"timestamp":"[^"]+","key":"key-1","value":null,"headers":\[\{"key":"header-key","value":"header-value"\}\]\}\n`),
+			},
+		},
+		{
+			name:      "consume binary messages with auto encoding",
+			topicName: "consume-topic-auto-encoding",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-auto-encoding", Value: []byte("printable")},
+				{Topic: "consume-topic-auto-encoding", Value: []byte{0xff, 0x00}},
+			},
+			getArgs: []string{"consume", "consume-topic-auto-encoding", "--offset", "earliest", "--timeout", "5", "--value-encoding", "auto"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`printable\n/wA=\n`),
+			},
+		},
+		{
+			name:          "consume messages with invalid template format",
+			topicName:     "consume-topic-invalid-template",
+			getArgs:       []string{"consume", "consume-topic-invalid-template", "--format", "{{.Value"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid format template`),
+			},
+		},
+		{
+			name:          "consume without group with invalid offset",
+			topicName:     "consume-topic-no-group-invalid",
+			getArgs:       []string{"consume", "consume-topic-no-group-invalid", "--no-group", "--offset", "yesterday"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid offset argument. Use 'earliest', 'latest', an exact offset or a RFC3339 timestamp`),
+			},
+		},
+		{
+			name:                 "consume transactions read uncommitted",
+			topicName:            "consume-topic-read-uncommitted",
+			abortedTransaction:   []string{"Aborted 1"},
+			committedTransaction: []string{"Committed 1"},
+			getArgs:              []string{"consume", "consume-topic-read-uncommitted", "--offset", "earliest", "--exit-at-end", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Aborted 1\nCommitted 1\n$`),
+			},
+		},
+		{
+			name:                 "consume transactions read committed",
+			topicName:            "consume-topic-read-committed",
+			abortedTransaction:   []string{"Aborted 1", "Aborted 2"},
+			committedTransaction: []string{"Committed 1"},
+			getArgs:              []string{"consume", "consume-topic-read-committed", "--offset", "earliest", "--exit-at-end", "--isolation", "read_committed", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Committed 1\n$`),
+			},
+		},
+		{
+			name:                 "consume transactions with control records",
+			topicName:            "consume-topic-control-records",
+			abortedTransaction:   []string{"Aborted 1"},
+			committedTransaction: []string{"Committed 1"},
+			getArgs:              []string{"consume", "consume-topic-control-records", "--offset", "earliest", "--exit-at-end", "--show-control-records", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Aborted 1\n<ABORT marker, producer id \d+>\nCommitted 1\n<COMMIT marker, producer id \d+>\n$`),
+			},
+		},
+		{
+			name:                 "consume transactions read committed with control records",
+			topicName:            "consume-topic-read-committed-control-records",
+			abortedTransaction:   []string{"Aborted 1"},
+			committedTransaction: []string{"Committed 1"},
+			getArgs:              []string{"consume", "consume-topic-read-committed-control-records", "--offset", "earliest", "--exit-at-end", "--isolation", "read_committed", "--show-control-records", "--format", "{{.Offset}} {{.Control}}", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^1 ABORT\n2 \n3 COMMIT\n$`),
+			},
+		},
+		{
+			name:      "consume avro messages with the schema registry",
+			topicName: "consume-topic-avro",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-avro", Value: test_helpers.AvroWireFormat(t, 1, userSchema, map[string]any{"name": "alice", "age": 30})},
+				{Topic: "consume-topic-avro", Value: []byte("not avro")},
+			},
+			getArgs: []string{"consume", "consume-topic-avro", "--offset", "earliest", "--exit-at-end", "--value-decoder", "schema-registry", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^\{"name":"alice","age":30\}\nnot avro\n$`),
+			},
+		},
+		{
+			name:      "consume protobuf messages with the schema registry",
+			topicName: "consume-topic-protobuf",
+			produceRecords: []*kgo.Record{
+				// Schema id 2 followed by the index of the first message of the schema
+				{Topic: "consume-topic-protobuf", Value: append([]byte{0, 0, 0, 0, 2, 0}, userProtobuf...)},
+			},
+			getArgs: []string{"consume", "consume-topic-protobuf", "--offset", "earliest", "--exit-at-end", "--value-decoder", "schema-registry", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^\{"name":"bob","age":25\}\n$`),
+			},
+		},
+		{
+			name:      "consume protobuf messages with a descriptor set",
+			topicName: "consume-topic-protobuf-descriptor-set",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-protobuf-descriptor-set", Value: userProtobuf},
+			},
+			getArgs: []string{"consume", "consume-topic-protobuf-descriptor-set", "--offset", "earliest", "--exit-at-end", "--proto-descriptor-set", userDescriptorSet, "--proto-message", "shop.User", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^\{"name":"bob","age":25\}\n$`),
+			},
+		},
+		{
+			name:      "consume protobuf messages of a topic of the cluster config",
+			topicName: "consume-topic-protobuf-config",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-protobuf-config", Value: userProtobuf},
+			},
+			getArgs: []string{"consume", "consume-topic-protobuf-config", "--offset", "earliest", "--exit-at-end", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^\{"name":"bob","age":25\}\n$`),
+			},
+		},
+		{
+			name:          "consume with an unknown protobuf message",
+			topicName:     "consume-topic-protobuf-unknown-message",
+			getArgs:       []string{"consume", "consume-topic-protobuf-unknown-message", "--proto-descriptor-set", userDescriptorSet, "--proto-message", "shop.Order"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: no message 'shop.Order' in the descriptor set`),
+			},
+		},
+		{
+			name:      "consume json schema messages with the schema registry",
+			topicName: "consume-topic-json-schema",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-json-schema", Value: append([]byte{0, 0, 0, 0, 3}, `{"name": "carol"}`...)},
+			},
+			getArgs: []string{"consume", "consume-topic-json-schema", "--offset", "earliest", "--exit-at-end", "--value-decoder", "schema-registry", "--format", "json", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`"value": "\{\\"name\\":\\"carol\\"\}"`),
+			},
+		},
+		{
+			name:      "consume avro messages with an unknown schema",
+			topicName: "consume-topic-avro-unknown-schema",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-avro-unknown-schema", Value: test_helpers.AvroWireFormat(t, 42, userSchema, map[string]any{"name": "alice", "age": 30})},
+			},
+			getArgs:       []string{"consume", "consume-topic-avro-unknown-schema", "--offset", "earliest", "--exit-at-end", "--value-decoder", "schema-registry", "--timeout", "30s"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: error decoding value: error fetching schema 42 from the schema registry`),
+			},
+		},
+		{
+			name:      "consume raw avro messages with a schema file",
+			topicName: "consume-topic-avro-schema-file",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-avro-schema-file", Value: userAvro},
+			},
+			getArgs: []string{"consume", "consume-topic-avro-schema-file", "--offset", "earliest", "--exit-at-end", "--value-decoder", "avro:" + userSchemaFile, "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^\{"name":"alice","age":30\}\n$`),
+			},
+		},
+		{
+			name:      "consume messages with an exec decoder",
+			topicName: "consume-topic-exec-decoder",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-exec-decoder", Key: []byte("key"), Value: []byte("hello")},
+			},
+			getArgs: []string{"consume", "consume-topic-exec-decoder", "--offset", "earliest", "--exit-at-end", "--value-decoder", "exec:tr a-z A-Z", "--format", "{{.Key}} {{.Value}}", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^key HELLO\n$`),
+			},
+		},
+		{
+			name:      "consume messages with the decoders of a topic of the cluster config",
+			topicName: "consume-topic-decoders-config",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-decoders-config", Key: []byte("key"), Value: []byte(`{"id": 1}`)},
+			},
+			getArgs: []string{"consume", "consume-topic-decoders-config", "--offset", "earliest", "--exit-at-end", "--format", "{{.Key}} {{.Value}}", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^KEY \{"id":1\}\n$`),
+			},
+		},
+		{
+			name:      "consume messages with a decoder flag overriding the cluster config",
+			topicName: "consume-topic-decoders-override",
+			produceRecords: []*kgo.Record{
+				{Topic: "consume-topic-decoders-override", Key: []byte("key"), Value: []byte(`{"id": 1}`)},
+			},
+			getArgs: []string{"consume", "consume-topic-decoders-override", "--offset", "earliest", "--exit-at-end", "--key-decoder", "none", "--format", "{{.Key}} {{.Value}}", "--timeout", "30s"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^key \{"id":1\}\n$`),
+			},
+		},
+		{
+			name:          "consume with invalid decoder",
+			topicName:     "consume-topic-invalid-decoder",
+			getArgs:       []string{"consume", "consume-topic-invalid-decoder", "--key-decoder", "thrift"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid --key-decoder 'thrift': unknown decoder. Use one of: none, text, json, schema-registry, avro:<schema.avsc>`),
+			},
+		},
+		{
+			name:          "consume with invalid isolation",
+			topicName:     "consume-topic-invalid-isolation",
+			getArgs:       []string{"consume", "consume-topic-invalid-isolation", "--isolation", "serializable"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid --isolation 'serializable'. Use one of: read_uncommitted, read_committed`),
 			},
 		},
 	}
@@ -103,7 +555,7 @@ func TestConsumeMessages(t *testing.T) {
 			tempDir := test_helpers.SetupTest(t, testConfig)
 			defer test_helpers.CleanupTestConfig(t, tempDir)
 
-			_, err = adminClient.CreateTopics(ctx, 1, 1, nil, tt.topicName)
+			_, err = adminClient.CreateTopics(ctx, 1, 1, nil, append([]string{tt.topicName}, tt.extraTopics...)...)
 			assert.NoError(t, err)
 
 			var wg sync.WaitGroup
@@ -120,12 +572,24 @@ func TestConsumeMessages(t *testing.T) {
 				for _, msg := range tt.produceMessages {
 					test_helpers.ProduceMessage(t, cl, tt.topicName, msg)
 				}
+				for _, record := range tt.produceRecords {
+					test_helpers.ProduceRecord(t, cl, record)
+				}
+				if len(tt.abortedTransaction) > 0 {
+					test_helpers.ProduceTransaction(t, brokers, tt.topicName, tt.abortedTransaction, false)
+				}
+				if len(tt.committedTransaction) > 0 {
+					test_helpers.ProduceTransaction(t, brokers, tt.topicName, tt.committedTransaction, true)
+				}
 			}
 
 			output, err := test_helpers.ExecuteCommandWrapper(tt.getArgs)
 			for _, pattern := range tt.expectedPatterns {
 				assert.Regexp(t, pattern, output)
 			}
+			for _, pattern := range tt.unexpectedPatterns {
+				assert.NotRegexp(t, pattern, output)
+			}
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -134,6 +598,20 @@ func TestConsumeMessages(t *testing.T) {
 			}
 
 			wg.Wait()
+
+			if tt.expectNoCommit {
+				committedOffsets, err := adminClient.FetchOffsets(ctx, "test-group")
+				assert.NoError(t, err)
+				_, ok := committedOffsets[tt.topicName]
+				assert.False(t, ok, "Expected no committed offsets for topic %s", tt.topicName)
+			}
+			if tt.expectedCommitAt > 0 {
+				committedOffsets, err := adminClient.FetchOffsets(ctx, "test-group")
+				assert.NoError(t, err)
+				committedOffset, ok := committedOffsets.Lookup(tt.topicName, 0)
+				assert.True(t, ok, "Expected committed offsets for topic %s", tt.topicName)
+				assert.Equal(t, tt.expectedCommitAt, committedOffset.At)
+			}
 		})
 	}
 }
@@ -0,0 +1,91 @@
+package consume
+
+import (
+	"math"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// stopConditions decides which records are printed and when consume stops,
+// from --max-messages, --until-offset, --until-time and --exit-at-end
+type stopConditions struct {
+	maxMessages int
+	consumed    int
+	untilTime   time.Time
+	// showControlRecords prints the control records, which are otherwise only used to track offsets
+	showControlRecords bool
+	// remaining holds, for each partition that is not done yet, the offset at which it will be.
+	// It is nil when partitions do not need to be tracked.
+	remaining map[string]map[int32]int64
+}
+
+// newStopConditions builds the stop conditions. startOffsets and endOffsets are only used when exitAtEnd is set or
+// untilOffset is not negative, and a partition which already starts at its target offset is done from the start.
+func newStopConditions(maxMessages int, untilOffset int64, untilTime time.Time, exitAtEnd bool, startOffsets map[string]map[int32]int64, endOffsets map[string]map[int32]int64) *stopConditions {
+	conditions := &stopConditions{maxMessages: maxMessages, untilTime: untilTime}
+	trackOffsets := exitAtEnd || untilOffset >= 0
+	if !trackOffsets && untilTime.IsZero() {
+		return conditions
+	}
+
+	conditions.remaining = make(map[string]map[int32]int64)
+	for topic, partitions := range endOffsets {
+		conditions.remaining[topic] = make(map[int32]int64)
+		for partition, endOffset := range partitions {
+			target := int64(math.MaxInt64)
+			if exitAtEnd {
+				target = endOffset
+			}
+			if untilOffset >= 0 && untilOffset < target {
+				target = untilOffset
+			}
+			if trackOffsets && startOffsets[topic][partition] >= target {
+				continue
+			}
+			conditions.remaining[topic][partition] = target
+		}
+	}
+	return conditions
+}
+
+// accept reports whether record should be printed, and marks its partition as done once it reached its target offset
+// or went past --until-time. Control records are tracked, but only printed with showControlRecords.
+func (s *stopConditions) accept(record *kgo.Record) bool {
+	if s.maxMessages > 0 && s.consumed >= s.maxMessages {
+		return false
+	}
+	if s.remaining != nil {
+		target, ok := s.remaining[record.Topic][record.Partition]
+		if !ok {
+			return false
+		}
+		if record.Offset >= target || (!s.untilTime.IsZero() && record.Timestamp.After(s.untilTime)) {
+			delete(s.remaining[record.Topic], record.Partition)
+			return false
+		}
+		if record.Offset+1 >= target {
+			delete(s.remaining[record.Topic], record.Partition)
+		}
+	}
+	if record.Attrs.IsControl() && !s.showControlRecords {
+		return false
+	}
+	s.consumed++
+	return true
+}
+
+func (s *stopConditions) done() bool {
+	if s.maxMessages > 0 && s.consumed >= s.maxMessages {
+		return true
+	}
+	if s.remaining == nil {
+		return false
+	}
+	for _, partitions := range s.remaining {
+		if len(partitions) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package consume
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestStopConditions(t *testing.T) {
+	baseTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := func(partition int32, offset int64) *kgo.Record {
+		return &kgo.Record{Topic: "topic", Partition: partition, Offset: offset, Timestamp: baseTime.Add(time.Duration(offset) * time.Minute)}
+	}
+
+	tests := []struct {
+		name             string
+		maxMessages      int
+		untilOffset      int64
+		untilTime        time.Time
+		exitAtEnd        bool
+		startOffsets     map[string]map[int32]int64
+		endOffsets       map[string]map[int32]int64
+		records          []*kgo.Record
+		expectedAccepted []bool
+		expectedDone     bool
+	}{
+		{
+			name:             "no condition never stops",
+			untilOffset:      -1,
+			endOffsets:       map[string]map[int32]int64{"topic": {0: 2}},
+			records:          []*kgo.Record{record(0, 0), record(0, 1), record(0, 2)},
+			expectedAccepted: []bool{true, true, true},
+			expectedDone:     false,
+		},
+		{
+			name:             "max messages",
+			maxMessages:      2,
+			untilOffset:      -1,
+			records:          []*kgo.Record{record(0, 0), record(1, 0), record(0, 1)},
+			expectedAccepted: []bool{true, true, false},
+			expectedDone:     true,
+		},
+		{
+			name:             "exit at end waits for every partition",
+			untilOffset:      -1,
+			exitAtEnd:        true,
+			startOffsets:     map[string]map[int32]int64{"topic": {0: 0, 1: 0}},
+			endOffsets:       map[string]map[int32]int64{"topic": {0: 2, 1: 1}},
+			records:          []*kgo.Record{record(0, 0), record(0, 1), record(0, 2)},
+			expectedAccepted: []bool{true, true, false},
+			expectedDone:     false,
+		},
+		{
+			name:             "exit at end with every partition consumed",
+			untilOffset:      -1,
+			exitAtEnd:        true,
+			startOffsets:     map[string]map[int32]int64{"topic": {0: 1, 1: 0, 2: 5}},
+			endOffsets:       map[string]map[int32]int64{"topic": {0: 2, 1: 1, 2: 5}},
+			records:          []*kgo.Record{record(0, 1), record(1, 0)},
+			expectedAccepted: []bool{true, true},
+			expectedDone:     true,
+		},
+		{
+			name:         "exit at end with nothing to consume",
+			untilOffset:  -1,
+			exitAtEnd:    true,
+			startOffsets: map[string]map[int32]int64{"topic": {0: 3}},
+			endOffsets:   map[string]map[int32]int64{"topic": {0: 3}},
+			expectedDone: true,
+		},
+		{
+			name:             "until offset",
+			untilOffset:      2,
+			startOffsets:     map[string]map[int32]int64{"topic": {0: 0, 1: 4}},
+			endOffsets:       map[string]map[int32]int64{"topic": {0: 10, 1: 10}},
+			records:          []*kgo.Record{record(0, 0), record(0, 1), record(0, 2)},
+			expectedAccepted: []bool{true, true, false},
+			expectedDone:     true,
+		},
+		{
+			name:             "until offset and exit at end stop at the lowest",
+			untilOffset:      5,
+			exitAtEnd:        true,
+			startOffsets:     map[string]map[int32]int64{"topic": {0: 0}},
+			endOffsets:       map[string]map[int32]int64{"topic": {0: 1}},
+			records:          []*kgo.Record{record(0, 0)},
+			expectedAccepted: []bool{true},
+			expectedDone:     true,
+		},
+		{
+			name:             "until time",
+			untilOffset:      -1,
+			untilTime:        baseTime.Add(90 * time.Second),
+			endOffsets:       map[string]map[int32]int64{"topic": {0: 10}},
+			records:          []*kgo.Record{record(0, 0), record(0, 1), record(0, 2), record(0, 3)},
+			expectedAccepted: []bool{true, true, false, false},
+			expectedDone:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stop := newStopConditions(tt.maxMessages, tt.untilOffset, tt.untilTime, tt.exitAtEnd, tt.startOffsets, tt.endOffsets)
+			accepted := make([]bool, 0, len(tt.records))
+			for _, record := range tt.records {
+				accepted = append(accepted, stop.accept(record))
+			}
+			if len(tt.expectedAccepted) > 0 {
+				assert.Equal(t, tt.expectedAccepted, accepted)
+			}
+			assert.Equal(t, tt.expectedDone, stop.done())
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var aclCmd = &cobra.Command{
+	Use:   "acl --principal <principal> --resource-type <type> [--resource-name <name>] --operation <operation> [--host <host>] [--pattern-type <pattern>] [--permission <permission>]",
+	Short: "Create ACLs",
+	Long: `Create the ACLs of principals on resources.
+
+An ACL is created for each principal, host, resource name and operation. Principals without a type are users, alice
+being User:alice. ACLs allow access from any host by default:
+- kacao create acl --principal alice --resource-type topic --resource-name orders --operation read,describe
+- kacao create acl --principal User:billing --resource-type group --resource-name billing --operation read
+- kacao create acl --principal alice --resource-type cluster --operation describe-configs
+
+With --pattern-type prefixed, the ACLs apply to all the resources whose name starts with --resource-name:
+- kacao create acl --principal billing --resource-type topic --resource-name billing- --pattern-type prefixed --operation all
+
+Access is denied with --permission deny, which takes precedence over the ACLs which allow it:
+- kacao create acl --principal mallory --host 10.0.0.1 --resource-type topic --resource-name orders --operation write --permission deny`,
+	Args: cobra.NoArgs,
+	RunE: func(command *cobra.Command, args []string) error {
+		spec, err := cmd.GetACLFlags(command, true)
+		if err != nil {
+			return err
+		}
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		consumerGroup, err := cmd.GetConsumerGroup()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(
+			kgo.SeedBrokers(boostrapServers...),
+			kgo.ConsumerGroup(consumerGroup),
+		)
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		createACLsResults, err := adminClient.CreateACLs(context.Background(), spec.Builder())
+		if err != nil {
+			return fmt.Errorf("error creating ACLs: %v", err)
+		}
+
+		failedToCreateAnyACL := false
+		for _, result := range createACLsResults {
+			description := fmt.Sprintf("%s %s of %s from host %s on %s '%s' (%s)", cmd.FormatACLEnum(result.Permission),
+				cmd.FormatACLEnum(result.Operation), result.Principal, result.Host, cmd.FormatACLEnum(result.Type), result.Name,
+				cmd.FormatACLEnum(result.Pattern))
+			if result.Err != nil {
+				_, err := fmt.Fprintf(command.OutOrStdout(), "Error creating ACL to %s: %s: %s\n", description, result.Err, result.ErrMessage)
+				cobra.CheckErr(err)
+				failedToCreateAnyACL = true
+			} else {
+				_, err := fmt.Fprintf(command.OutOrStdout(), "Created ACL to %s\n", description)
+				cobra.CheckErr(err)
+			}
+		}
+
+		if failedToCreateAnyACL {
+			return fmt.Errorf("failed to create one or more ACLs")
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.AddACLFlags(aclCmd, true)
+	createCmd.AddCommand(aclCmd)
+}
@@ -0,0 +1,141 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestCreateACL(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+		testcontainers.WithEnv(map[string]string{
+			"KAFKA_AUTHORIZER_CLASS_NAME": "org.apache.kafka.metadata.authorizer.StandardAuthorizer",
+			"KAFKA_SUPER_USERS":           "User:ANONYMOUS",
+		}),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+		expectedACLs     int
+	}{
+		{
+			name: "create acls of a user on a topic",
+			args: []string{"create", "acl", "--principal", "alice", "--resource-type", "topic", "--resource-name", "orders", "--operation", "read,describe"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Created ACL to allow read of User:alice from host \* on topic 'orders' \(literal\)\n`),
+				regexp.MustCompile(`Created ACL to allow describe of User:alice from host \* on topic 'orders' \(literal\)\n`),
+			},
+			expectedACLs: 2,
+		},
+		{
+			name: "create a prefixed acl denying a host",
+			args: []string{"create", "acl", "--principal", "User:mallory", "--host", "10.0.0.1", "--resource-type", "group", "--resource-name", "billing-",
+				"--pattern-type", "prefixed", "--operation", "read", "--permission", "deny"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Created ACL to deny read of User:mallory from host 10.0.0.1 on group 'billing-' \(prefixed\)\n`),
+			},
+			expectedACLs: 1,
+		},
+		{
+			name: "create a cluster acl",
+			args: []string{"create", "acl", "--principal", "alice", "--resource-type", "cluster", "--operation", "describe-configs"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Created ACL to allow describe-configs of User:alice from host \* on cluster 'kafka-cluster' \(literal\)\n`),
+			},
+			expectedACLs: 1,
+		},
+		{
+			name:          "missing operation",
+			args:          []string{"create", "acl", "--principal", "alice", "--resource-type", "topic", "--resource-name", "orders"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: required flag\(s\) "operation" not set`),
+			},
+		},
+		{
+			name:          "missing resource name",
+			args:          []string{"create", "acl", "--principal", "alice", "--resource-type", "topic", "--operation", "read"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: --resource-name is required to create ACLs of resource type topic`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+			allACLs := kadm.NewACLs().AnyResource().Allow().Deny().AllowHosts().DenyHosts().Operations().ResourcePatternType(kadm.ACLPatternAny)
+			defer func() {
+				_, err := adminClient.DeleteACLs(ctx, allACLs)
+				assert.NoError(t, err)
+			}()
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+
+			results, err := adminClient.DescribeACLs(ctx, allACLs)
+			assert.NoError(t, err)
+			acls := 0
+			for _, result := range results {
+				assert.NoError(t, result.Err)
+				acls += len(result.Described)
+			}
+			assert.Equal(t, tt.expectedACLs, acls)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/Vidalee/kacao/serde"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/sr"
+	"strings"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema <subject_name> --file <schema_file> [--reference <name=subject:version>]",
+	Short: "Register a schema in the schema registry",
+	Long: `Register a schema under a subject of the schema registry of the current cluster.
+
+The type of the schema is inferred from the extension of the file: .avsc for Avro, .proto for Protobuf and .json for
+JSON Schema:
+- kacao create schema orders-value --file order.avsc
+
+The schema is first checked against the latest version of the subject with its compatibility level, and is not
+registered if it is incompatible. Use --dry-run to only check it. Registering a schema which is already the latest
+version of the subject does not create a new version.
+
+Schemas using types of other subjects list them with --reference, by the name used in the schema (the full name of an
+Avro type, or the import path of a Protobuf file):
+- kacao create schema orders-value --file order.avsc --reference com.example.Item=item-value:1`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(command *cobra.Command, args []string) error {
+		subject := args[0]
+		file, err := command.Flags().GetString("file")
+		cobra.CheckErr(err)
+		referenceArgs, err := command.Flags().GetStringArray("reference")
+		cobra.CheckErr(err)
+		dryRun, err := command.Flags().GetBool("dry-run")
+		cobra.CheckErr(err)
+
+		schema, err := serde.ReadSchemaFile(file)
+		if err != nil {
+			return err
+		}
+		for _, referenceArg := range referenceArgs {
+			reference, err := cmd.ParseReference(referenceArg)
+			if err != nil {
+				return err
+			}
+			schema.References = append(schema.References, reference)
+		}
+
+		client, err := cmd.NewSchemaRegistryClient()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		latest, err := client.SchemaByVersion(ctx, subject, serde.LatestVersion)
+		var responseError *sr.ResponseError
+		switch {
+		case errors.As(err, &responseError) && (responseError.ErrorCode == sr.ErrSubjectNotFound.Code || responseError.ErrorCode == sr.ErrVersionNotFound.Code):
+			_, err = fmt.Fprintf(command.OutOrStdout(), "Subject '%s' does not exist, no compatibility check needed\n", subject)
+			cobra.CheckErr(err)
+		case err != nil:
+			return fmt.Errorf("error fetching latest schema of subject '%s': %v", subject, err)
+		default:
+			compatibility, err := client.CheckCompatibility(sr.WithParams(ctx, sr.Verbose), subject, latest.Version, schema)
+			if err != nil {
+				return fmt.Errorf("error checking compatibility with version %d of subject '%s': %v", latest.Version, subject, err)
+			}
+			if !compatibility.Is {
+				return fmt.Errorf("schema is incompatible with version %d of subject '%s': %s", latest.Version, subject, strings.Join(compatibility.Messages, "; "))
+			}
+			_, err = fmt.Fprintf(command.OutOrStdout(), "Schema is compatible with version %d of subject '%s'\n", latest.Version, subject)
+			cobra.CheckErr(err)
+		}
+
+		if dryRun {
+			return nil
+		}
+		created, err := client.CreateSchema(ctx, subject, schema)
+		if err != nil {
+			return fmt.Errorf("error registering schema under subject '%s': %v", subject, err)
+		}
+		_, err = fmt.Fprintf(command.OutOrStdout(), "Registered schema %d as version %d of subject '%s'\n", created.ID, created.Version, subject)
+		cobra.CheckErr(err)
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.Flags().StringP("file", "f", "", "File of the schema: .avsc for Avro, .proto for Protobuf or .json for JSON Schema")
+	schemaCmd.Flags().StringArray("reference", []string{}, "Reference to a schema of another subject, example: --reference com.example.Item=item-value:1")
+	schemaCmd.Flags().Bool("dry-run", false, "Only check the compatibility of the schema, without registering it")
+	err := schemaCmd.MarkFlagRequired("file")
+	cobra.CheckErr(err)
+	createCmd.AddCommand(schemaCmd)
+}
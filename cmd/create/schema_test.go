@@ -0,0 +1,153 @@
+package get
+
+import (
+	"bytes"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCreateSchema(t *testing.T) {
+	registry := srfake.New()
+	defer registry.Close()
+	registry.SeedSchema("item-value", 1, 1, sr.Schema{Schema: `{"type":"record","name":"Item","fields":[{"name":"name","type":"string"}]}`})
+	registry.SeedSchema("orders-value", 1, 2, sr.Schema{Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"long"}]}`})
+	// The fake registry considers every schema compatible, except the ones with an "incompatible" field
+	registry.Intercept(func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method != http.MethodPost || !strings.HasPrefix(r.URL.Path, "/compatibility/") {
+			return false
+		}
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if !bytes.Contains(body, []byte("incompatible")) {
+			return false
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"is_compatible": false, "messages": ["field 'id' was removed"]}`))
+		return true
+	})
+
+	schemasDir := t.TempDir()
+	schemaFiles := map[string]string{
+		"order.avsc":        `{"type":"record","name":"Order","fields":[{"name":"id","type":"long"},{"name":"note","type":"string","default":""}]}`,
+		"incompatible.avsc": `{"type":"record","name":"Order","fields":[{"name":"incompatible","type":"string"}]}`,
+		"basket.avsc":       `{"type":"record","name":"Basket","fields":[{"name":"items","type":{"type":"array","items":"Item"}}]}`,
+		"user.proto":        `syntax = "proto3"; message User { string name = 1; }`,
+		"order.txt":         `order`,
+	}
+	for name, content := range schemaFiles {
+		assert.NoError(t, os.WriteFile(filepath.Join(schemasDir, name), []byte(content), 0644))
+	}
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": []string{"localhost:9092"},
+				"schema-registry":   registry.URL(),
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+	tempDir := test_helpers.SetupTest(t, testConfig)
+	defer test_helpers.CleanupTestConfig(t, tempDir)
+
+	tests := []struct {
+		name             string
+		createArgs       []string
+		expectedOutput   string
+		expectedError    bool
+		expectedErrorMsg string
+		verifyRegistry   func(t *testing.T, client *sr.Client)
+	}{
+		{
+			name:           "create compatible schema",
+			createArgs:     []string{"create", "schema", "orders-value", "--file", filepath.Join(schemasDir, "order.avsc")},
+			expectedOutput: "Schema is compatible with version 1 of subject 'orders-value'\nRegistered schema 3 as version 2 of subject 'orders-value'\n",
+		},
+		{
+			name:           "create schema of a new subject",
+			createArgs:     []string{"create", "schema", "users-value", "--file", filepath.Join(schemasDir, "user.proto")},
+			expectedOutput: "Subject 'users-value' does not exist, no compatibility check needed\nRegistered schema 4 as version 1 of subject 'users-value'\n",
+			verifyRegistry: func(t *testing.T, client *sr.Client) {
+				schema, err := client.SchemaByVersion(t.Context(), "users-value", 1)
+				assert.NoError(t, err)
+				assert.Equal(t, sr.TypeProtobuf, schema.Type)
+			},
+		},
+		{
+			name:           "create schema with a reference",
+			createArgs:     []string{"create", "schema", "baskets-value", "--file", filepath.Join(schemasDir, "basket.avsc"), "--reference", "Item=item-value:1"},
+			expectedOutput: "Subject 'baskets-value' does not exist, no compatibility check needed\nRegistered schema 5 as version 1 of subject 'baskets-value'\n",
+			verifyRegistry: func(t *testing.T, client *sr.Client) {
+				schema, err := client.SchemaByVersion(t.Context(), "baskets-value", 1)
+				assert.NoError(t, err)
+				assert.Equal(t, []sr.SchemaReference{{Name: "Item", Subject: "item-value", Version: 1}}, schema.References)
+			},
+		},
+		{
+			name:           "dry run",
+			createArgs:     []string{"create", "schema", "item-value", "--file", filepath.Join(schemasDir, "order.avsc"), "--dry-run"},
+			expectedOutput: "Schema is compatible with version 1 of subject 'item-value'\n",
+			verifyRegistry: func(t *testing.T, client *sr.Client) {
+				versions, err := client.SubjectVersions(t.Context(), "item-value")
+				assert.NoError(t, err)
+				assert.Equal(t, []int{1}, versions)
+			},
+		},
+		{
+			name:             "create incompatible schema",
+			createArgs:       []string{"create", "schema", "item-value", "--file", filepath.Join(schemasDir, "incompatible.avsc")},
+			expectedError:    true,
+			expectedErrorMsg: "Error: schema is incompatible with version 1 of subject 'item-value': field 'id' was removed",
+		},
+		{
+			name:             "create schema with unknown type",
+			createArgs:       []string{"create", "schema", "orders-value", "--file", filepath.Join(schemasDir, "order.txt")},
+			expectedError:    true,
+			expectedErrorMsg: "Error: unknown type of schema",
+		},
+		{
+			name:             "create schema with invalid reference",
+			createArgs:       []string{"create", "schema", "orders-value", "--file", filepath.Join(schemasDir, "order.avsc"), "--reference", "Item"},
+			expectedError:    true,
+			expectedErrorMsg: "Error: invalid reference 'Item'. Expected name=subject:version",
+		},
+		{
+			name:             "create schema without file",
+			createArgs:       []string{"create", "schema", "orders-value"},
+			expectedError:    true,
+			expectedErrorMsg: `Error: required flag(s) "file" not set`,
+		},
+	}
+
+	client, err := sr.NewClient(sr.URLs(registry.URL()))
+	assert.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := test_helpers.ExecuteCommandWrapper(tt.createArgs)
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.Regexp(t, regexp.MustCompile(regexp.QuoteMeta(tt.expectedErrorMsg)), output)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOutput, output)
+			if tt.verifyRegistry != nil {
+				tt.verifyRegistry(t, client)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package get
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"strings"
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user <user_name> --password-stdin [--mechanism <mechanism>] [--iterations <iterations>]",
+	Short: "Create or update the SCRAM credentials of a user",
+	Long: `Create the SCRAM credentials of a user, or update its password if the user has credentials of the mechanism.
+
+The password is read from the first line of the standard input, so that it does not appear in the shell history or in
+the list of processes:
+- kacao create user billing-service --password-stdin < password.txt
+- echo "$PASSWORD" | kacao create user billing-service --mechanism SCRAM-SHA-256 --password-stdin
+
+A user can have credentials of both mechanisms, created one at a time.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(command *cobra.Command, args []string) error {
+		user := args[0]
+		passwordStdin, err := command.Flags().GetBool("password-stdin")
+		cobra.CheckErr(err)
+		if !passwordStdin {
+			return errors.New("--password-stdin is required, passwords are not accepted as arguments")
+		}
+		mechanismName, err := command.Flags().GetString("mechanism")
+		cobra.CheckErr(err)
+		mechanism, err := cmd.ParseScramMechanism(mechanismName)
+		if err != nil {
+			return err
+		}
+		iterations, err := command.Flags().GetInt32("iterations")
+		cobra.CheckErr(err)
+
+		password, err := bufio.NewReader(command.InOrStdin()).ReadString('\n')
+		if err != nil && password == "" {
+			return errors.New("no password on the standard input")
+		}
+		password = strings.TrimRight(password, "\r\n")
+		if password == "" {
+			return errors.New("no password on the standard input")
+		}
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		results, err := adminClient.AlterUserSCRAMs(context.Background(), nil, []kadm.UpsertSCRAM{{
+			User:       user,
+			Mechanism:  mechanism,
+			Iterations: iterations,
+			Password:   password,
+		}})
+		if err != nil {
+			return fmt.Errorf("error creating user '%s': %v", user, err)
+		}
+		for _, result := range results.Sorted() {
+			if result.Err != nil {
+				if result.ErrMessage != "" {
+					return fmt.Errorf("error creating user '%s': %v: %s", result.User, result.Err, result.ErrMessage)
+				}
+				return fmt.Errorf("error creating user '%s': %v", result.User, result.Err)
+			}
+		}
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "Created %s credentials of user '%s'\n", mechanism, user)
+		cobra.CheckErr(err)
+		return nil
+	},
+}
+
+func init() {
+	userCmd.Flags().Bool("password-stdin", false, "Read the password from the standard input")
+	userCmd.Flags().String("mechanism", kadm.ScramSha512.String(), "SCRAM mechanism of the credentials: "+cmd.ScramMechanismsUsage)
+	userCmd.Flags().Int32("iterations", 8192, "Number of iterations of the SCRAM hash, between 4096 and 16384")
+	createCmd.AddCommand(userCmd)
+}
@@ -0,0 +1,148 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestCreateUser(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		stdin            string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+		// Credentials of the user of the command after it
+		expectedCredInfos []kadm.CredInfo
+	}{
+		{
+			name:  "create a user",
+			args:  []string{"create", "user", "alice", "--password-stdin"},
+			stdin: "alice-secret\n",
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Created SCRAM-SHA-512 credentials of user 'alice'\n$`),
+			},
+			expectedCredInfos: []kadm.CredInfo{{Mechanism: kadm.ScramSha512, Iterations: 8192}},
+		},
+		{
+			name:  "create a user with a mechanism and iterations",
+			args:  []string{"create", "user", "billing-service", "--password-stdin", "--mechanism", "scram-sha-256", "--iterations", "4096"},
+			stdin: "billing-secret",
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Created SCRAM-SHA-256 credentials of user 'billing-service'\n$`),
+			},
+			expectedCredInfos: []kadm.CredInfo{{Mechanism: kadm.ScramSha256, Iterations: 4096}},
+		},
+		{
+			name:          "too few iterations",
+			args:          []string{"create", "user", "carol", "--password-stdin", "--iterations", "1000"},
+			stdin:         "carol-secret\n",
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: error creating user 'carol': UNACCEPTABLE_CREDENTIAL`),
+			},
+		},
+		{
+			name:          "without password-stdin",
+			args:          []string{"create", "user", "carol"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: --password-stdin is required, passwords are not accepted as arguments`),
+			},
+		},
+		{
+			name:          "empty password",
+			args:          []string{"create", "user", "carol", "--password-stdin"},
+			stdin:         "\n",
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: no password on the standard input`),
+			},
+		},
+		{
+			name:          "unknown mechanism",
+			args:          []string{"create", "user", "carol", "--password-stdin", "--mechanism", "PLAIN"},
+			stdin:         "carol-secret\n",
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: unknown SCRAM mechanism 'PLAIN'. Use SCRAM-SHA-256 or SCRAM-SHA-512`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+			cmd.RootCmd.SetIn(strings.NewReader(tt.stdin))
+			defer cmd.RootCmd.SetIn(nil)
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+
+			if tt.expectedCredInfos == nil {
+				return
+			}
+			users, err := adminClient.DescribeUserSCRAMs(ctx, tt.args[2])
+			assert.NoError(t, err)
+			assert.NoError(t, users.Error())
+			assert.Equal(t, tt.expectedCredInfos, users[tt.args[2]].CredInfos)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package delete
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"slices"
+)
+
+var aclCmd = &cobra.Command{
+	Use:   "acl [--principal <principal>] [--host <host>] [--resource-type <type>] [--resource-name <name>] [--pattern-type <pattern>] [--operation <operation>] [--permission <permission>] [--dry-run]",
+	Short: "Delete the ACLs matching filters",
+	Long: `Delete the ACLs matching filters.
+
+The filters are those of 'kacao get acls', at least one of which is required, unset filters matching anything. The
+matched ACLs are displayed before being deleted. Use --dry-run to only display them:
+- kacao delete acl --principal alice --dry-run
+- kacao delete acl --principal alice --resource-type topic --resource-name orders --operation write
+- kacao delete acl --resource-type topic --resource-name billing- --pattern-type prefixed`,
+	Args: cobra.NoArgs,
+	RunE: func(command *cobra.Command, args []string) error {
+		if !slices.ContainsFunc(cmd.ACLFilterFlags, command.Flags().Changed) {
+			return fmt.Errorf("at least one filter is required to delete ACLs, use --resource-type any to delete all ACLs")
+		}
+		spec, err := cmd.GetACLFlags(command, false)
+		if err != nil {
+			return err
+		}
+		dryRun, err := command.Flags().GetBool("dry-run")
+		cobra.CheckErr(err)
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		consumerGroup, err := cmd.GetConsumerGroup()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(
+			kgo.SeedBrokers(boostrapServers...),
+			kgo.ConsumerGroup(consumerGroup),
+		)
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		ctx := context.Background()
+		acls, err := cmd.DescribeACLs(ctx, adminClient, spec.Builder())
+		if err != nil {
+			return err
+		}
+		if len(acls) == 0 {
+			return fmt.Errorf("no ACL matches the filters")
+		}
+		_, err = fmt.Fprintf(command.OutOrStdout(), "ACLs matching the filters:\n")
+		cobra.CheckErr(err)
+		cobra.CheckErr(cmd.PrintACLs(command.OutOrStdout(), acls))
+		if dryRun {
+			return nil
+		}
+
+		deleteACLsResults, err := adminClient.DeleteACLs(ctx, spec.Builder())
+		if err != nil {
+			return fmt.Errorf("error deleting ACLs: %v", err)
+		}
+		deleted := 0
+		failedToDeleteAnyACL := false
+		for _, result := range deleteACLsResults {
+			if result.Err != nil {
+				_, err := fmt.Fprintf(command.OutOrStdout(), "Error deleting ACLs: %s: %s\n", result.Err, result.ErrMessage)
+				cobra.CheckErr(err)
+				failedToDeleteAnyACL = true
+				continue
+			}
+			for _, deletedACL := range result.Deleted {
+				if deletedACL.Err != nil {
+					_, err := fmt.Fprintf(command.OutOrStdout(), "Error deleting ACL to %s %s of %s on %s '%s': %s: %s\n",
+						cmd.FormatACLEnum(deletedACL.Permission), cmd.FormatACLEnum(deletedACL.Operation), deletedACL.Principal,
+						cmd.FormatACLEnum(deletedACL.Type), deletedACL.Name, deletedACL.Err, deletedACL.ErrMessage)
+					cobra.CheckErr(err)
+					failedToDeleteAnyACL = true
+					continue
+				}
+				deleted++
+			}
+		}
+		_, err = fmt.Fprintf(command.OutOrStdout(), "Deleted %d ACL(s)\n", deleted)
+		cobra.CheckErr(err)
+
+		if failedToDeleteAnyACL {
+			return fmt.Errorf("failed to delete one or more ACLs")
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd.AddACLFlags(aclCmd, false)
+	aclCmd.Flags().Bool("dry-run", false, "Only display the ACLs matching the filters, without deleting them")
+	deleteCmd.AddCommand(aclCmd)
+}
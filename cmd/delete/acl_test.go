@@ -0,0 +1,175 @@
+package delete
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestDeleteACL(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+		testcontainers.WithEnv(map[string]string{
+			"KAFKA_AUTHORIZER_CLASS_NAME": "org.apache.kafka.metadata.authorizer.StandardAuthorizer",
+			"KAFKA_SUPER_USERS":           "User:ANONYMOUS",
+		}),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+		// ACLs left after the command, of the 4 ACLs created before it
+		expectedACLs int
+	}{
+		{
+			name: "delete acls of a principal",
+			args: []string{"delete", "acl", "--principal", "alice"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^ACLs matching the filters:\nPrincipal\s+Host\s+Resource type\s+Resource name\s+Pattern\s+Operation\s+Permission\s+\n` +
+					`User:alice\s+\*\s+topic\s+orders\s+literal\s+read\s+allow\s+\n` +
+					`User:alice\s+\*\s+topic\s+orders\s+literal\s+write\s+allow\s+\n` +
+					`Deleted 2 ACL\(s\)\n$`),
+			},
+			expectedACLs: 2,
+		},
+		{
+			name: "delete acls of a resource and operation",
+			args: []string{"delete", "acl", "--resource-type", "topic", "--resource-name", "orders", "--operation", "write"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`\nUser:alice\s+\*\s+topic\s+orders\s+literal\s+write\s+allow\s+\nDeleted 1 ACL\(s\)\n$`),
+			},
+			expectedACLs: 3,
+		},
+		{
+			name: "delete prefixed acls",
+			args: []string{"delete", "acl", "--pattern-type", "prefixed"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`\nUser:bob\s+\*\s+group\s+billing-\s+prefixed\s+read\s+deny\s+\nDeleted 1 ACL\(s\)\n$`),
+			},
+			expectedACLs: 3,
+		},
+		{
+			name: "delete all acls",
+			args: []string{"delete", "acl", "--resource-type", "any"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Deleted 4 ACL\(s\)\n$`),
+			},
+			expectedACLs: 0,
+		},
+		{
+			name: "dry run",
+			args: []string{"delete", "acl", "--principal", "bob", "--dry-run"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^ACLs matching the filters:\nPrincipal\s+Host\s+Resource type\s+Resource name\s+Pattern\s+Operation\s+Permission\s+\n` +
+					`User:bob\s+\*\s+cluster\s+kafka-cluster\s+literal\s+describe\s+allow\s+\n` +
+					`User:bob\s+\*\s+group\s+billing-\s+prefixed\s+read\s+deny\s+\n$`),
+			},
+			expectedACLs: 4,
+		},
+		{
+			name:          "no acl matching the filters",
+			args:          []string{"delete", "acl", "--principal", "carol"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: no ACL matches the filters`),
+			},
+			expectedACLs: 4,
+		},
+		{
+			name:          "no filter",
+			args:          []string{"delete", "acl"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: at least one filter is required to delete ACLs, use --resource-type any to delete all ACLs`),
+			},
+			expectedACLs: 4,
+		},
+	}
+
+	allACLs := kadm.NewACLs().AnyResource().Allow().Deny().AllowHosts().DenyHosts().Operations().ResourcePatternType(kadm.ACLPatternAny)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+			defer func() {
+				_, err := adminClient.DeleteACLs(ctx, allACLs)
+				assert.NoError(t, err)
+			}()
+
+			for _, builder := range []*kadm.ACLBuilder{
+				kadm.NewACLs().Allow("User:alice").Topics("orders").Operations(kadm.OpRead, kadm.OpWrite).ResourcePatternType(kadm.ACLPatternLiteral),
+				kadm.NewACLs().Allow("User:bob").Clusters().Operations(kadm.OpDescribe).ResourcePatternType(kadm.ACLPatternLiteral),
+				kadm.NewACLs().Deny("User:bob").Groups("billing-").Operations(kadm.OpRead).ResourcePatternType(kadm.ACLPatternPrefixed),
+			} {
+				results, err := adminClient.CreateACLs(ctx, builder)
+				assert.NoError(t, err)
+				for _, result := range results {
+					assert.NoError(t, result.Err)
+				}
+			}
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+
+			results, err := adminClient.DescribeACLs(ctx, allACLs)
+			assert.NoError(t, err)
+			acls := 0
+			for _, result := range results {
+				assert.NoError(t, result.Err)
+				acls += len(result.Described)
+			}
+			assert.Equal(t, tt.expectedACLs, acls)
+		})
+	}
+}
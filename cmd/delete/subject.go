@@ -0,0 +1,65 @@
+package delete
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+var subjectCmd = &cobra.Command{
+	Use:   "subject",
+	Short: "Delete a subject of the schema registry",
+	Long: `Delete one or many subjects of the schema registry of the current cluster, with all their versions.
+
+Subjects are soft deleted by default: their schemas can still be fetched by id, and messages serialized with them can
+still be decoded. Use --permanent to also hard delete them, which cannot be undone:
+
+- kacao delete subject <subject_name>
+- kacao delete subject <subject_name_1> <subject_name_2> ... --permanent`,
+	Example: "kacao delete subject <subject_name>",
+	RunE: func(command *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return command.Help()
+		}
+		permanent, err := command.Flags().GetBool("permanent")
+		cobra.CheckErr(err)
+
+		client, err := cmd.NewSchemaRegistryClient()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		failedToDeleteAnySubject := false
+		for _, subject := range args {
+			versions, err := client.DeleteSubject(ctx, subject, sr.SoftDelete)
+			if err == nil && permanent {
+				versions, err = client.DeleteSubject(ctx, subject, sr.HardDelete)
+			}
+			if err != nil {
+				_, err := fmt.Fprintf(command.OutOrStdout(), "Failed to delete subject '%s': %v\n", subject, err)
+				cobra.CheckErr(err)
+				failedToDeleteAnySubject = true
+				continue
+			}
+			if permanent {
+				_, err = fmt.Fprintf(command.OutOrStdout(), "Permanently deleted subject '%s', versions %v\n", subject, versions)
+			} else {
+				_, err = fmt.Fprintf(command.OutOrStdout(), "Deleted subject '%s', versions %v\n", subject, versions)
+			}
+			cobra.CheckErr(err)
+		}
+
+		if failedToDeleteAnySubject {
+			return fmt.Errorf("failed to delete one or more subjects")
+		}
+		return nil
+	},
+}
+
+func init() {
+	subjectCmd.Flags().Bool("permanent", false, "Hard delete the subjects after soft deleting them")
+	deleteCmd.AddCommand(subjectCmd)
+}
@@ -0,0 +1,92 @@
+package delete
+
+import (
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+	"testing"
+)
+
+func TestDeleteSubject(t *testing.T) {
+	registry := srfake.New()
+	defer registry.Close()
+	registry.SeedSchema("orders-value", 1, 1, sr.Schema{Schema: `{"type": "string"}`})
+	registry.SeedSchema("orders-value", 2, 2, sr.Schema{Schema: `{"type": "long"}`})
+	registry.SeedSchema("users-value", 1, 3, sr.Schema{Schema: `{"type": "string"}`})
+	registry.SeedSchema("payments-value", 1, 4, sr.Schema{Schema: `{"type": "string"}`})
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": []string{"localhost:9092"},
+				"schema-registry":   registry.URL(),
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+	tempDir := test_helpers.SetupTest(t, testConfig)
+	defer test_helpers.CleanupTestConfig(t, tempDir)
+
+	client, err := sr.NewClient(sr.URLs(registry.URL()))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		deleteArgs     []string
+		expectedOutput string
+		expectedError  bool
+		verifyRegistry func(t *testing.T)
+	}{
+		{
+			name:           "soft delete subject",
+			deleteArgs:     []string{"delete", "subject", "orders-value"},
+			expectedOutput: "Deleted subject 'orders-value', versions [1 2]\n",
+			verifyRegistry: func(t *testing.T) {
+				subjects, err := client.Subjects(t.Context())
+				assert.NoError(t, err)
+				assert.NotContains(t, subjects, "orders-value")
+				subjects, err = client.Subjects(sr.WithParams(t.Context(), sr.ShowDeleted))
+				assert.NoError(t, err)
+				assert.Contains(t, subjects, "orders-value")
+			},
+		},
+		{
+			name:           "permanently delete subject",
+			deleteArgs:     []string{"delete", "subject", "users-value", "--permanent"},
+			expectedOutput: "Permanently deleted subject 'users-value', versions [1]\n",
+			verifyRegistry: func(t *testing.T) {
+				subjects, err := client.Subjects(sr.WithParams(t.Context(), sr.ShowDeleted))
+				assert.NoError(t, err)
+				assert.NotContains(t, subjects, "users-value")
+			},
+		},
+		{
+			name:           "delete unknown subject",
+			deleteArgs:     []string{"delete", "subject", "unknown-value", "payments-value"},
+			expectedOutput: "Failed to delete subject 'unknown-value': subject \"unknown-value\" not found\nDeleted subject 'payments-value', versions [1]\n",
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := test_helpers.ExecuteCommandWrapper(tt.deleteArgs)
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.Contains(t, output, tt.expectedOutput)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOutput, output)
+			if tt.verifyRegistry != nil {
+				tt.verifyRegistry(t)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package delete
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user <user_name> [<user_name> ...] [--mechanism <mechanism>]",
+	Short: "Delete the SCRAM credentials of users",
+	Long: `Delete the SCRAM credentials of one or many users, of all their mechanisms or of one.
+
+- kacao delete user billing-service
+- kacao delete user billing-service --mechanism SCRAM-SHA-256
+
+The users can no longer authenticate with the deleted credentials. Their ACLs and quotas are left unchanged.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(command *cobra.Command, args []string) error {
+		var mechanism kadm.ScramMechanism
+		if command.Flags().Changed("mechanism") {
+			mechanismName, err := command.Flags().GetString("mechanism")
+			cobra.CheckErr(err)
+			mechanism, err = cmd.ParseScramMechanism(mechanismName)
+			if err != nil {
+				return err
+			}
+		}
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		ctx := context.Background()
+
+		users, err := adminClient.DescribeUserSCRAMs(ctx, args...)
+		if err != nil {
+			return fmt.Errorf("error describing users: %v", err)
+		}
+		var deletions []kadm.DeleteSCRAM
+		for _, user := range args {
+			described, ok := users[user]
+			if !ok || described.Err != nil || len(described.CredInfos) == 0 {
+				return fmt.Errorf("user '%s' has no SCRAM credentials", user)
+			}
+			found := false
+			for _, credInfo := range described.CredInfos {
+				if mechanism == 0 || credInfo.Mechanism == mechanism {
+					deletions = append(deletions, kadm.DeleteSCRAM{User: user, Mechanism: credInfo.Mechanism})
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("user '%s' has no %s credentials", user, mechanism)
+			}
+		}
+
+		results, err := adminClient.AlterUserSCRAMs(ctx, deletions, nil)
+		if err != nil {
+			return fmt.Errorf("error deleting users: %v", err)
+		}
+		failedToDeleteAnyUser := false
+		for _, result := range results.Sorted() {
+			if result.Err != nil {
+				_, err := fmt.Fprintf(command.OutOrStdout(), "Failed to delete credentials of user '%s': %v: %s\n", result.User, result.Err, result.ErrMessage)
+				cobra.CheckErr(err)
+				failedToDeleteAnyUser = true
+			}
+		}
+		for _, deletion := range deletions {
+			if results[deletion.User].Err == nil {
+				_, err := fmt.Fprintf(command.OutOrStdout(), "Deleted %s credentials of user '%s'\n", deletion.Mechanism, deletion.User)
+				cobra.CheckErr(err)
+			}
+		}
+
+		if failedToDeleteAnyUser {
+			return fmt.Errorf("failed to delete one or more users")
+		}
+		return nil
+	},
+}
+
+func init() {
+	userCmd.Flags().String("mechanism", "", "Only delete the credentials of a SCRAM mechanism: "+cmd.ScramMechanismsUsage)
+	deleteCmd.AddCommand(userCmd)
+}
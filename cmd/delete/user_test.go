@@ -0,0 +1,141 @@
+package delete
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+		// Users left after the command, of alice with both mechanisms and billing-service created before it
+		expectedUsers map[string]int
+	}{
+		{
+			name: "delete all credentials of a user",
+			args: []string{"delete", "user", "alice"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Deleted SCRAM-SHA-256 credentials of user 'alice'\n`),
+				regexp.MustCompile(`Deleted SCRAM-SHA-512 credentials of user 'alice'\n`),
+			},
+			expectedUsers: map[string]int{"billing-service": 1},
+		},
+		{
+			name: "delete credentials of a mechanism",
+			args: []string{"delete", "user", "alice", "--mechanism", "SCRAM-SHA-256"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Deleted SCRAM-SHA-256 credentials of user 'alice'\n$`),
+			},
+			expectedUsers: map[string]int{"alice": 1, "billing-service": 1},
+		},
+		{
+			name: "delete many users",
+			args: []string{"delete", "user", "alice", "billing-service"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Deleted SCRAM-SHA-512 credentials of user 'billing-service'\n`),
+			},
+			expectedUsers: map[string]int{},
+		},
+		{
+			name:          "delete an unknown user",
+			args:          []string{"delete", "user", "carol"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: user 'carol' has no SCRAM credentials`),
+			},
+			expectedUsers: map[string]int{"alice": 2, "billing-service": 1},
+		},
+		{
+			name:          "delete a missing mechanism",
+			args:          []string{"delete", "user", "billing-service", "--mechanism", "SCRAM-SHA-256"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: user 'billing-service' has no SCRAM-SHA-256 credentials`),
+			},
+			expectedUsers: map[string]int{"alice": 2, "billing-service": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+
+			results, err := adminClient.AlterUserSCRAMs(ctx, nil, []kadm.UpsertSCRAM{
+				{User: "alice", Mechanism: kadm.ScramSha512, Iterations: 8192, Password: "alice-secret"},
+				{User: "alice", Mechanism: kadm.ScramSha256, Iterations: 8192, Password: "alice-secret"},
+				{User: "billing-service", Mechanism: kadm.ScramSha512, Iterations: 8192, Password: "billing-secret"},
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, results.Error())
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+
+			users, err := adminClient.DescribeUserSCRAMs(ctx)
+			assert.NoError(t, err)
+			actualUsers := make(map[string]int)
+			for _, user := range users {
+				actualUsers[user.User] = len(user.CredInfos)
+			}
+			assert.Equal(t, tt.expectedUsers, actualUsers)
+		})
+	}
+}
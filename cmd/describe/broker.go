@@ -0,0 +1,119 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var brokerCmd = &cobra.Command{
+	Use:   "broker <broker_id> [<broker_id> ...]",
+	Short: "Describe a broker of the current cluster",
+	Long: `Describe a broker of the current cluster, with its configs and where their values come from
+
+- kacao describe broker 1
+- kacao describe broker 1 2 3
+
+The source of a config is one of:
+- default: the default value of Kafka
+- static: the value of the server.properties file of the broker
+- dynamic-broker: a value set on the broker with 'kacao set config broker <broker_id>'
+- dynamic-default: a value set on all brokers with 'kacao set config broker --all'
+- dynamic-broker-logger: a log level set on the broker
+
+Sensitive values, such as passwords, are masked.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(command *cobra.Command, args []string) error {
+		brokerIDs := make([]int32, 0, len(args))
+		for _, arg := range args {
+			brokerID, err := strconv.ParseInt(arg, 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid broker id '%s'", arg)
+			}
+			brokerIDs = append(brokerIDs, int32(brokerID))
+		}
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		ctx := context.Background()
+
+		metadata, err := adminClient.BrokerMetadata(ctx)
+		if err != nil {
+			return fmt.Errorf("error fetching brokers of the cluster: %v", err)
+		}
+		brokerDetails := make(map[int32]kadm.BrokerDetail)
+		for _, brokerID := range brokerIDs {
+			index := slices.IndexFunc(metadata.Brokers, func(broker kadm.BrokerDetail) bool {
+				return broker.NodeID == brokerID
+			})
+			if index < 0 {
+				return fmt.Errorf("broker %d does not exist in the cluster", brokerID)
+			}
+			brokerDetails[brokerID] = metadata.Brokers[index]
+		}
+
+		resourceConfigs, err := adminClient.DescribeBrokerConfigs(ctx, brokerIDs...)
+		if err != nil {
+			return fmt.Errorf("error describing configs of brokers: %v", err)
+		}
+
+		for i, brokerID := range brokerIDs {
+			if i > 0 {
+				_, err := fmt.Fprintln(command.OutOrStdout())
+				cobra.CheckErr(err)
+			}
+			brokerDetail := brokerDetails[brokerID]
+			rack := "-"
+			if brokerDetail.Rack != nil {
+				rack = *brokerDetail.Rack
+			}
+			_, err := fmt.Fprintf(command.OutOrStdout(), "%-25s%d\n", "ID: ", brokerDetail.NodeID)
+			cobra.CheckErr(err)
+			_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%s\n", "Host: ", brokerDetail.Host)
+			cobra.CheckErr(err)
+			_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%d\n", "Port: ", brokerDetail.Port)
+			cobra.CheckErr(err)
+			_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%s\n", "Rack: ", rack)
+			cobra.CheckErr(err)
+			_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%v\n", "Controller: ", metadata.Controller == brokerID)
+			cobra.CheckErr(err)
+
+			resourceConfig, err := resourceConfigs.On(strconv.Itoa(int(brokerID)), nil)
+			if err == nil {
+				err = resourceConfig.Err
+			}
+			if err != nil {
+				return fmt.Errorf("error describing configs of broker %d: %v", brokerID, err)
+			}
+			configs := slices.Clone(resourceConfig.Configs)
+			slices.SortFunc(configs, func(a, b kadm.Config) int {
+				return strings.Compare(a.Key, b.Key)
+			})
+			_, err = fmt.Fprintf(command.OutOrStdout(), "Configs:\n")
+			cobra.CheckErr(err)
+			_, err = fmt.Fprintf(command.OutOrStdout(), "  %-60s%-40s%-20s\n", "Config", "Value", "Source")
+			cobra.CheckErr(err)
+			for _, config := range configs {
+				_, err = fmt.Fprintf(command.OutOrStdout(), "  %-60s%-40s%-20s\n", config.Key, cmd.FormatConfigValue(config), cmd.ConfigSourceName(config.Source))
+				cobra.CheckErr(err)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	describeCmd.AddCommand(brokerCmd)
+}
@@ -0,0 +1,115 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestDescribeBroker(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	// Dynamic configs of the broker and of all brokers
+	threads := "2"
+	_, err = adminClient.AlterBrokerConfigs(ctx, []kadm.AlterConfig{{Op: kadm.SetConfig, Name: "log.cleaner.threads", Value: &threads}}, 1)
+	assert.NoError(t, err)
+	rate := "10485760"
+	_, err = adminClient.AlterBrokerConfigs(ctx, []kadm.AlterConfig{{Op: kadm.SetConfig, Name: "leader.replication.throttled.rate", Value: &rate}})
+	assert.NoError(t, err)
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+	}{
+		{
+			name: "describe broker with config sources",
+			args: []string{"describe", "broker", "1"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`ID:\s+1\nHost:\s+\S+\nPort:\s+\d+\nRack:\s+-\nController:\s+(true|false)\nConfigs:\n\s+Config\s+Value\s+Source`),
+				regexp.MustCompile(`\n\s+log.cleaner.threads\s+2\s+dynamic-broker\s+\n`),
+				regexp.MustCompile(`\n\s+leader.replication.throttled.rate\s+10485760\s+dynamic-default\s+\n`),
+				regexp.MustCompile(`\n\s+num.partitions\s+1\s+(static|default)\s+\n`),
+				regexp.MustCompile(`\n\s+log.retention.hours\s+\d+\s+(static|default)\s+\n`),
+			},
+		},
+		{
+			name:          "describe unknown broker",
+			args:          []string{"describe", "broker", "42"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: broker 42 does not exist in the cluster`),
+			},
+		},
+		{
+			name:          "describe invalid broker id",
+			args:          []string{"describe", "broker", "first"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid broker id 'first'`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+		})
+	}
+}
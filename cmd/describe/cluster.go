@@ -0,0 +1,101 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"slices"
+)
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Describe the current cluster",
+	Long: `Describe the current cluster, with the leaders and replicas of each broker, and the partitions whose leader is not
+their preferred replica
+
+The leaders of these partitions are moved back to their preferred replica with:
+- kacao elect leaders --preferred --all
+
+The unhealthy partitions of the cluster are displayed with 'kacao get partitions --under-replicated', --offline,
+--under-min-isr or --unavailable.`,
+	Args: cobra.NoArgs,
+	RunE: func(command *cobra.Command, args []string) error {
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		ctx := context.Background()
+		metadata, err := adminClient.Metadata(ctx)
+		if err != nil {
+			return fmt.Errorf("error describing the cluster: %v", err)
+		}
+
+		leaderCounts := make(map[int32]int)
+		replicaCounts := make(map[int32]int)
+		var notPreferred []kadm.PartitionDetail
+		for _, topicDetail := range metadata.Topics.Sorted() {
+			if topicDetail.Err != nil {
+				return fmt.Errorf("error describing topic '%s': %v", topicDetail.Topic, topicDetail.Err)
+			}
+			for _, partition := range topicDetail.Partitions.Sorted() {
+				if partition.Leader >= 0 {
+					leaderCounts[partition.Leader]++
+				}
+				for _, replica := range partition.Replicas {
+					replicaCounts[replica]++
+				}
+				if cmd.IsLeaderNotPreferred(partition) {
+					notPreferred = append(notPreferred, partition)
+				}
+			}
+		}
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%s\n", "Cluster ID: ", metadata.Cluster)
+		cobra.CheckErr(err)
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%s\n", "Controller: ", cmd.FormatLeader(metadata.Controller))
+		cobra.CheckErr(err)
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%d\n", "Brokers: ", len(metadata.Brokers))
+		cobra.CheckErr(err)
+
+		brokers := slices.Clone(metadata.Brokers)
+		slices.SortFunc(brokers, func(a, b kadm.BrokerDetail) int { return int(a.NodeID) - int(b.NodeID) })
+		_, err = fmt.Fprintf(command.OutOrStdout(), "\n%-10s%-40s%-20s%-10s%-10s\n", "Broker", "Host", "Rack", "Leaders", "Replicas")
+		cobra.CheckErr(err)
+		for _, broker := range brokers {
+			rack := "-"
+			if broker.Rack != nil {
+				rack = *broker.Rack
+			}
+			_, err = fmt.Fprintf(command.OutOrStdout(), "%-10d%-40s%-20s%-10d%-10d\n", broker.NodeID, fmt.Sprintf("%s:%d", broker.Host, broker.Port),
+				rack, leaderCounts[broker.NodeID], replicaCounts[broker.NodeID])
+			cobra.CheckErr(err)
+		}
+
+		if len(notPreferred) == 0 {
+			_, err = fmt.Fprintf(command.OutOrStdout(), "\nAll the partitions are led by their preferred replica\n")
+			cobra.CheckErr(err)
+			return nil
+		}
+		_, err = fmt.Fprintf(command.OutOrStdout(), "\nPartitions whose leader is not the preferred replica:\n")
+		cobra.CheckErr(err)
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-30s%-12s%-10s%-20s%s\n", "Topic", "Partition", "Leader", "Preferred leader", "Replicas")
+		cobra.CheckErr(err)
+		for _, partition := range notPreferred {
+			_, err = fmt.Fprintf(command.OutOrStdout(), "%-30s%-12d%-10s%-20d%v\n", partition.Topic, partition.Partition,
+				cmd.FormatLeader(partition.Leader), partition.Replicas[0], partition.Replicas)
+			cobra.CheckErr(err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	describeCmd.AddCommand(clusterCmd)
+}
@@ -0,0 +1,109 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestDescribeCluster(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	_, err = adminClient.CreateTopic(ctx, 3, 1, nil, "orders")
+	assert.NoError(t, err)
+	metadata, err := adminClient.Metadata(ctx)
+	assert.NoError(t, err)
+	partitionCount := 0
+	for _, topicDetail := range metadata.Topics {
+		partitionCount += len(topicDetail.Partitions)
+	}
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+	}{
+		{
+			name: "describe the cluster",
+			args: []string{"describe", "cluster"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Cluster ID:\s+` + regexp.QuoteMeta(metadata.Cluster) + `\n`),
+				regexp.MustCompile(`\nController:\s+1\n`),
+				regexp.MustCompile(`\nBrokers:\s+1\n`),
+				regexp.MustCompile(`\nBroker\s+Host\s+Rack\s+Leaders\s+Replicas\s+\n1\s+\S+:\d+\s+-\s+` +
+					fmt.Sprintf(`%d\s+%d\s+\n`, partitionCount, partitionCount)),
+				regexp.MustCompile(`\nAll the partitions are led by their preferred replica\n$`),
+			},
+		},
+		{
+			name:          "describe the cluster with an argument",
+			args:          []string{"describe", "cluster", "other"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: unknown command "other" for "kacao describe cluster"`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+var subjectCmd = &cobra.Command{
+	Use:   "subject <subject_name>",
+	Short: "Describe a subject of the schema registry",
+	Long: `Describe a subject of the schema registry of the current cluster: its compatibility level, its versions and its
+latest schema
+
+The compatibility level is the one of the subject, or the global one of the registry if the subject has none.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(command *cobra.Command, args []string) error {
+		subject := args[0]
+		client, err := cmd.NewSchemaRegistryClient()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		schemas, err := client.Schemas(ctx, subject)
+		if err != nil {
+			return fmt.Errorf("error fetching schemas of subject '%s': %v", subject, err)
+		}
+		compatibility := client.Compatibility(sr.WithParams(ctx, sr.DefaultToGlobal), subject)[0]
+		if compatibility.Err != nil {
+			return fmt.Errorf("error fetching compatibility of subject '%s': %v", subject, compatibility.Err)
+		}
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%s\n", "Subject: ", subject)
+		cobra.CheckErr(err)
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%s\n", "Compatibility: ", compatibility.Level)
+		cobra.CheckErr(err)
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%d\n", "Versions: ", len(schemas))
+		cobra.CheckErr(err)
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "\n%-15s%-15s%-15s%s\n", "Version", "Schema ID", "Type", "References")
+		cobra.CheckErr(err)
+		for _, schema := range schemas {
+			_, err = fmt.Fprintf(command.OutOrStdout(), "%-15d%-15d%-15s%s\n", schema.Version, schema.ID, schema.Type, cmd.FormatReferences(schema.References))
+			cobra.CheckErr(err)
+		}
+
+		if len(schemas) > 0 {
+			latest := schemas[len(schemas)-1]
+			_, err = fmt.Fprintf(command.OutOrStdout(), "\nSchema of version %d:\n%s\n", latest.Version, cmd.FormatSchema(latest.Schema))
+			cobra.CheckErr(err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	describeCmd.AddCommand(subjectCmd)
+}
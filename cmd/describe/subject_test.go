@@ -0,0 +1,88 @@
+package describe
+
+import (
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+	"regexp"
+	"testing"
+)
+
+func TestDescribeSubject(t *testing.T) {
+	registry := srfake.New()
+	defer registry.Close()
+	registry.SeedSchema("item-value", 1, 1, sr.Schema{Schema: `{"type":"record","name":"Item","fields":[{"name":"name","type":"string"}]}`})
+	registry.SeedSchema("orders-value", 1, 2, sr.Schema{Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"long"}]}`})
+	registry.SeedSchema("orders-value", 2, 3, sr.Schema{
+		Schema:     `{"type":"record","name":"Order","fields":[{"name":"id","type":"long"},{"name":"item","type":"Item"}]}`,
+		References: []sr.SchemaReference{{Name: "Item", Subject: "item-value", Version: 1}},
+	})
+	client, err := sr.NewClient(sr.URLs(registry.URL()))
+	assert.NoError(t, err)
+	result := client.SetCompatibility(t.Context(), sr.SetCompatibility{Level: sr.CompatFull}, "orders-value")
+	assert.NoError(t, result[0].Err)
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": []string{"localhost:9092"},
+				"schema-registry":   registry.URL(),
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+	tempDir := test_helpers.SetupTest(t, testConfig)
+	defer test_helpers.CleanupTestConfig(t, tempDir)
+
+	tests := []struct {
+		name             string
+		describeArgs     []string
+		expectedPatterns []*regexp.Regexp
+		expectedError    bool
+	}{
+		{
+			name:         "describe subject with versions",
+			describeArgs: []string{"describe", "subject", "orders-value"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Subject:\s+orders-value\nCompatibility:\s+FULL\nVersions:\s+2\n`),
+				regexp.MustCompile(`Version\s+Schema ID\s+Type\s+References\n1\s+2\s+AVRO\s+-\n2\s+3\s+AVRO\s+Item=item-value:1\n`),
+				regexp.MustCompile(`Schema of version 2:\n\{\n  "type": "record",\n  "name": "Order",`),
+			},
+		},
+		{
+			name:         "describe subject with the global compatibility",
+			describeArgs: []string{"describe", "subject", "item-value"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Compatibility:\s+BACKWARD\n`),
+			},
+		},
+		{
+			name:          "describe unknown subject",
+			describeArgs:  []string{"describe", "subject", "unknown-value"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: error fetching schemas of subject 'unknown-value'`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := test_helpers.ExecuteCommandWrapper(tt.describeArgs)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+		})
+	}
+}
@@ -7,15 +7,20 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 var topicCmd = &cobra.Command{
 	Use:   "topic",
 	Short: "Describe a topic of the current cluster",
-	Long: `Describe a topic of the current cluster
+	Long: `Describe a topic of the current cluster, with the leader and replicas of its partitions and its configs
 
 - kacao describe topic <topic_name>
-- kacao describe topic <topic_name_1> <topic_name_2> ...`,
+- kacao describe topic <topic_name_1> <topic_name_2> ...
+
+The source of each config is displayed: dynamic-topic for configs set on the topic, and default, static,
+dynamic-broker or dynamic-default for configs inherited from the brokers. --overrides-only only displays the configs
+set on the topic with a value which differs from the one of the brokers. Sensitive values are masked.`,
 	RunE: func(command *cobra.Command, args []string) error {
 		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
 		cobra.CheckErr(err)
@@ -30,6 +35,9 @@ var topicCmd = &cobra.Command{
 		defer cl.Close()
 		defer adminClient.Close()
 
+		overridesOnly, err := command.Flags().GetBool("overrides-only")
+		cobra.CheckErr(err)
+
 		ctx := context.Background()
 
 		topicDetails, err := (*kadm.Client).ListTopics(adminClient, ctx)
@@ -89,20 +97,31 @@ var topicCmd = &cobra.Command{
 			_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%d\n", "Message count: ", messageCount)
 			cobra.CheckErr(err)
 
+			_, err = fmt.Fprintf(command.OutOrStdout(), "Partitions:\n")
+			cobra.CheckErr(err)
+			_, err = fmt.Fprintf(command.OutOrStdout(), "  %-12s%-10s%-15s%-25s%-25s%-25s\n", "Partition", "Leader", "Leader epoch", "Replicas", "Synced replicas", "Offline replicas")
+			cobra.CheckErr(err)
+			for _, partitionDetail := range topicDetail.Partitions.Sorted() {
+				_, err = fmt.Fprintf(command.OutOrStdout(), "  %-12d%-10d%-15d%-25v%-25v%-25v\n", partitionDetail.Partition, partitionDetail.Leader,
+					partitionDetail.LeaderEpoch, partitionDetail.Replicas, partitionDetail.ISR, partitionDetail.OfflineReplicas)
+				cobra.CheckErr(err)
+			}
+
 			resourceConfig, ok := resourceConfigMap[topicDetail.Topic]
 			if !ok {
 				_, err = fmt.Fprintf(command.OutOrStdout(), "No resource config found for topic '%s'.\n", topicDetail.Topic)
 				cobra.CheckErr(err)
 				continue
 			}
-			_, err = fmt.Fprintf(command.OutOrStdout(), "Resource Configs:\n")
+			_, err = fmt.Fprintf(command.OutOrStdout(), "Configs:\n")
+			cobra.CheckErr(err)
+			_, err = fmt.Fprintf(command.OutOrStdout(), "  %-45s%-30s%-20s\n", "Config", "Value", "Source")
 			cobra.CheckErr(err)
 			for _, config := range resourceConfig.Configs {
-				if config.Sensitive {
-					_, err = fmt.Fprintf(command.OutOrStdout(), "  %s: %s (sensitive)\n", config.Key, *config.Value)
-				} else {
-					_, err = fmt.Fprintf(command.OutOrStdout(), "  %s: %s\n", config.Key, *config.Value)
+				if overridesOnly && !isTopicOverride(config) {
+					continue
 				}
+				_, err = fmt.Fprintf(command.OutOrStdout(), "  %-45s%-30s%-20s\n", config.Key, cmd.FormatConfigValue(config), cmd.ConfigSourceName(config.Source))
 				cobra.CheckErr(err)
 			}
 		}
@@ -134,6 +153,26 @@ func getMessageCount(startOffsets map[int32]kadm.ListedOffset, endOffsets map[in
 	return count
 }
 
+// isTopicOverride tells whether a config is set on the topic, with a value which differs from the one it would inherit
+// from the brokers
+func isTopicOverride(config kadm.Config) bool {
+	if config.Source != kmsg.ConfigSourceDynamicTopicConfig {
+		return false
+	}
+	for _, synonym := range config.Synonyms {
+		if synonym.Source == kmsg.ConfigSourceDynamicTopicConfig {
+			continue
+		}
+		// Synonyms are ordered by precedence, the first one being the value of the brokers
+		if config.Sensitive || synonym.Value == nil || config.Value == nil {
+			return true
+		}
+		return *synonym.Value != *config.Value
+	}
+	return true
+}
+
 func init() {
+	topicCmd.Flags().Bool("overrides-only", false, "Only display the configs set on the topic which differ from the configs of the brokers")
 	describeCmd.AddCommand(topicCmd)
 }
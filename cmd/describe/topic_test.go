@@ -11,6 +11,7 @@ import (
 
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 func TestDescribeTopic(t *testing.T) {
@@ -59,6 +60,7 @@ func TestDescribeTopic(t *testing.T) {
 		name             string
 		createTopics     []string
 		topicOptions     map[string]*string
+		partitions       int32
 		produceMessages  map[string]int // topic name -> number of messages to produce
 		describeArgs     []string
 		expectedError    bool
@@ -98,11 +100,37 @@ func TestDescribeTopic(t *testing.T) {
 			expectedOutput: []string{
 				"Name:                    config-topic",
 				"Partitions:              1",
-				"Resource Configs:",
-				"cleanup.policy: compact",
-				"retention.ms: 86400000",
+				"Partitions:",
+				fmt.Sprintf("  %-12s%-10s%-15s%-25s%-25s%-25s", "0", "1", "0", "[1]", "[1]", "[]"),
+				"Configs:",
+				fmt.Sprintf("  %-45s%-30s%-20s", "cleanup.policy", "compact", "dynamic-topic"),
+				fmt.Sprintf("  %-45s%-30s%-20s", "retention.ms", "86400000", "dynamic-topic"),
+				fmt.Sprintf("  %-45s%-30s%-20s", "segment.ms", "604800000", "default"),
 			},
 		},
+		{
+			name:         "describe topic overrides only",
+			createTopics: []string{"overrides-topic"},
+			topicOptions: map[string]*string{
+				"cleanup.policy": test_helpers.StringPtr("compact"),
+				// Same value as the default of the brokers
+				"segment.ms": test_helpers.StringPtr("604800000"),
+			},
+			describeArgs:  []string{"describe", "topic", "overrides-topic", "--overrides-only"},
+			expectedError: false,
+			expectedOutput: []string{
+				fmt.Sprintf("  %-45s%-30s%-20s", "cleanup.policy", "compact", "dynamic-topic"),
+			},
+			unexpectedOutput: []string{"segment.ms", "retention.ms", "default"},
+		},
+		{
+			name:           "describe topic with several partitions",
+			createTopics:   []string{"partitions-topic"},
+			partitions:     3,
+			describeArgs:   []string{"describe", "topic", "partitions-topic"},
+			expectedError:  false,
+			expectedOutput: []string{fmt.Sprintf("  %-12s%-10s", "0", "1"), fmt.Sprintf("  %-12s%-10s", "1", "1"), fmt.Sprintf("  %-12s%-10s", "2", "1")},
+		},
 		{
 			name:            "describe topic with messages",
 			createTopics:    []string{"messages-topic"},
@@ -138,7 +166,11 @@ func TestDescribeTopic(t *testing.T) {
 				if options == nil {
 					options = make(map[string]*string)
 				}
-				_, err := adminClient.CreateTopics(ctx, 1, 1, options, tt.createTopics...)
+				partitions := tt.partitions
+				if partitions == 0 {
+					partitions = 1
+				}
+				_, err := adminClient.CreateTopics(ctx, partitions, 1, options, tt.createTopics...)
 				assert.NoError(t, err, "Failed to create test topics")
 
 				topicsAfterCreate, err := adminClient.ListTopics(ctx)
@@ -179,3 +211,45 @@ func TestDescribeTopic(t *testing.T) {
 		})
 	}
 }
+
+func TestIsTopicOverride(t *testing.T) {
+	compact, deletePolicy := "compact", "delete"
+	tests := []struct {
+		name     string
+		config   kadm.Config
+		expected bool
+	}{
+		{
+			name:     "inherited config",
+			config:   kadm.Config{Key: "cleanup.policy", Value: &deletePolicy, Source: kmsg.ConfigSourceDefaultConfig},
+			expected: false,
+		},
+		{
+			name: "override of the broker config",
+			config: kadm.Config{Key: "cleanup.policy", Value: &compact, Source: kmsg.ConfigSourceDynamicTopicConfig, Synonyms: []kadm.ConfigSynonym{
+				{Key: "cleanup.policy", Value: &compact, Source: kmsg.ConfigSourceDynamicTopicConfig},
+				{Key: "log.cleanup.policy", Value: &deletePolicy, Source: kmsg.ConfigSourceDefaultConfig},
+			}},
+			expected: true,
+		},
+		{
+			name: "override with the value of the broker",
+			config: kadm.Config{Key: "cleanup.policy", Value: &deletePolicy, Source: kmsg.ConfigSourceDynamicTopicConfig, Synonyms: []kadm.ConfigSynonym{
+				{Key: "cleanup.policy", Value: &deletePolicy, Source: kmsg.ConfigSourceDynamicTopicConfig},
+				{Key: "log.cleanup.policy", Value: &deletePolicy, Source: kmsg.ConfigSourceStaticBrokerConfig},
+			}},
+			expected: false,
+		},
+		{
+			name:     "override without synonyms",
+			config:   kadm.Config{Key: "cleanup.policy", Value: &compact, Source: kmsg.ConfigSourceDynamicTopicConfig},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isTopicOverride(tt.config))
+		})
+	}
+}
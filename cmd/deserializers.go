@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Vidalee/kacao/serde"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Deserializers are selected by --key-decoder and --value-decoder, or per topic in the configuration of the cluster,
+// with a name followed by its argument for some of them: avro:order.avsc
+const (
+	DeserializerNone = "none"
+	DeserializerText = "text"
+	DeserializerJSON = "json"
+	// DeserializerSchemaRegistry decodes data in the Confluent wire format with the schema registry of the cluster
+	DeserializerSchemaRegistry = "schema-registry"
+	// DeserializerAvro decodes raw Avro data with a schema file: avro:<schema.avsc>
+	DeserializerAvro = "avro"
+	// DeserializerProtobuf decodes raw Protobuf data with a descriptor set: protobuf:<descriptor_set.pb>:<message>
+	DeserializerProtobuf = "protobuf"
+	// DeserializerExec pipes data to a shell command and reads its output: exec:<command>
+	DeserializerExec = "exec"
+)
+
+const DeserializersUsage = "none, text, json, schema-registry, avro:<schema.avsc>, protobuf:<descriptor_set.pb>:<message>, exec:<command>"
+
+// Deserializer turns serialized keys or values into readable text. It returns serde.ErrNotWireFormat for data it does
+// not handle, which is then rendered with the encoding of the field.
+type Deserializer interface {
+	Deserialize(data []byte) (string, error)
+}
+
+// TopicDeserializers are the deserializers of the keys and values of a topic
+type TopicDeserializers struct {
+	Key   Deserializer
+	Value Deserializer
+}
+
+type deserializerSpec struct {
+	name string
+	// file is the schema or descriptor set of avro and protobuf
+	file     string
+	message  string
+	command  string
+	original string
+}
+
+func parseDeserializerSpec(spec string) (deserializerSpec, error) {
+	name, argument, _ := strings.Cut(spec, ":")
+	parsed := deserializerSpec{name: name, original: spec}
+	switch name {
+	case DeserializerNone, DeserializerText, DeserializerJSON, DeserializerSchemaRegistry:
+		if argument != "" {
+			return parsed, fmt.Errorf("decoder %s takes no argument", name)
+		}
+	case DeserializerAvro:
+		if argument == "" {
+			return parsed, errors.New("missing schema file. Use avro:<schema.avsc>")
+		}
+		parsed.file = argument
+	case DeserializerProtobuf:
+		// Paths may contain colons, messages cannot
+		separator := strings.LastIndex(argument, ":")
+		if separator <= 0 || separator == len(argument)-1 {
+			return parsed, errors.New("missing descriptor set or message. Use protobuf:<descriptor_set.pb>:<message>")
+		}
+		parsed.file, parsed.message = argument[:separator], argument[separator+1:]
+	case DeserializerExec:
+		if argument == "" {
+			return parsed, errors.New("missing command. Use exec:<command>")
+		}
+		parsed.command = argument
+	default:
+		return parsed, fmt.Errorf("unknown decoder. Use one of: %s", DeserializersUsage)
+	}
+	return parsed, nil
+}
+
+// NormalizeDeserializerSpec validates a deserializer, and makes its files absolute to use it from any directory
+func NormalizeDeserializerSpec(spec string) (string, error) {
+	parsed, err := parseDeserializerSpec(spec)
+	if err != nil {
+		return "", err
+	}
+	if parsed.file == "" {
+		return spec, nil
+	}
+	file, err := filepath.Abs(parsed.file)
+	if err != nil {
+		return "", err
+	}
+	if parsed.name == DeserializerProtobuf {
+		return parsed.name + ":" + file + ":" + parsed.message, nil
+	}
+	return parsed.name + ":" + file, nil
+}
+
+// deserializerFactory creates deserializers, which share the schema registry client and the descriptor sets
+type deserializerFactory struct {
+	registry       *serde.SchemaRegistryDeserializer
+	descriptorSets map[string]*protoregistry.Files
+}
+
+func newDeserializerFactory() *deserializerFactory {
+	return &deserializerFactory{descriptorSets: make(map[string]*protoregistry.Files)}
+}
+
+// New returns the deserializer of spec, or nil for none
+func (f *deserializerFactory) New(spec string) (Deserializer, error) {
+	parsed, err := parseDeserializerSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	switch parsed.name {
+	case DeserializerText:
+		return serde.TextDeserializer{}, nil
+	case DeserializerJSON:
+		return serde.JSONDeserializer{}, nil
+	case DeserializerSchemaRegistry:
+		if f.registry == nil {
+			client, err := NewSchemaRegistryClient()
+			if err != nil {
+				return nil, err
+			}
+			f.registry = serde.NewSchemaRegistryDeserializer(client)
+		}
+		return f.registry, nil
+	case DeserializerAvro:
+		return serde.NewAvroDeserializer(parsed.file)
+	case DeserializerProtobuf:
+		files, ok := f.descriptorSets[parsed.file]
+		if !ok {
+			files, err = serde.LoadDescriptorSet(parsed.file)
+			if err != nil {
+				return nil, err
+			}
+			f.descriptorSets[parsed.file] = files
+		}
+		return serde.NewProtobufDeserializer(files, parsed.message)
+	case DeserializerExec:
+		return serde.NewExecDeserializer(parsed.command), nil
+	}
+	return nil, nil
+}
+
+// topicDeserializers returns the deserializers of the topics of the current cluster, from its configuration
+func (f *deserializerFactory) topicDeserializers() (map[string]TopicDeserializers, error) {
+	clusterName, err := GetCurrentClusterName()
+	if err != nil {
+		return nil, err
+	}
+	topicDecoders, err := GetClusterTopicDecoders(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	deserializers := make(map[string]TopicDeserializers, len(topicDecoders))
+	for _, topicDecoder := range topicDecoders {
+		topicDeserializers := deserializers[topicDecoder.Topic]
+		for _, field := range []struct {
+			name         string
+			spec         string
+			deserializer *Deserializer
+		}{
+			{"key", topicDecoder.Key, &topicDeserializers.Key},
+			{"value", topicDecoder.Value, &topicDeserializers.Value},
+		} {
+			if field.spec == "" || *field.deserializer != nil {
+				continue
+			}
+			deserializer, err := f.New(field.spec)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s decoder '%s' of topic '%s': %v", field.name, field.spec, topicDecoder.Topic, err)
+			}
+			*field.deserializer = deserializer
+		}
+		deserializers[topicDecoder.Topic] = topicDeserializers
+	}
+	return deserializers, nil
+}
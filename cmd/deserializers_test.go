@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDeserializerSpec(t *testing.T) {
+	workingDirectory, err := filepath.Abs(".")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		spec          string
+		expected      string
+		expectedError string
+	}{
+		{spec: "none", expected: "none"},
+		{spec: "schema-registry", expected: "schema-registry"},
+		{spec: "exec:tr a-z A-Z", expected: "exec:tr a-z A-Z"},
+		{spec: "avro:/schemas/order.avsc", expected: "avro:/schemas/order.avsc"},
+		{spec: "avro:order.avsc", expected: "avro:" + filepath.Join(workingDirectory, "order.avsc")},
+		{spec: "protobuf:shop.pb:shop.Order", expected: "protobuf:" + filepath.Join(workingDirectory, "shop.pb") + ":shop.Order"},
+		{spec: "protobuf:/protos/shop.pb:shop.Order", expected: "protobuf:/protos/shop.pb:shop.Order"},
+		{spec: "thrift", expectedError: "unknown decoder"},
+		{spec: "json:strict", expectedError: "decoder json takes no argument"},
+		{spec: "avro", expectedError: "missing schema file"},
+		{spec: "protobuf:shop.pb", expectedError: "missing descriptor set or message"},
+		{spec: "protobuf:shop.pb:", expectedError: "missing descriptor set or message"},
+		{spec: "exec:", expectedError: "missing command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			spec, err := NormalizeDeserializerSpec(tt.spec)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, spec)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package elect
+
+import (
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+)
+
+var electCmd = &cobra.Command{
+	Use:   "elect",
+	Short: "Elect the leaders of partitions",
+	Long:  `Elect the leaders of partitions`,
+}
+
+func init() {
+	cmd.RootCmd.AddCommand(electCmd)
+}
@@ -0,0 +1,135 @@
+package elect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var leadersCmd = &cobra.Command{
+	Use:   "leaders --preferred|--unclean --topic <topic_name> [--partition <partition_id>[,<partition_id>...]] | --all",
+	Short: "Elect the leaders of partitions",
+	Long: `Elect the leaders of partitions, of a topic or of all the topics of the cluster.
+
+With --preferred, the preferred replica of the partitions, their first replica, becomes their leader when it is in sync.
+This balances the leaders again after brokers restarted:
+- kacao elect leaders --preferred --all
+- kacao elect leaders --preferred --topic orders --partition 0,3
+
+With --unclean, partitions which have no leader elect a live replica as their leader, even when it is not in sync.
+Messages which were not copied to this replica are lost, this is a last resort when the in-sync replicas are gone:
+- kacao elect leaders --unclean --topic orders --partition 2
+
+Partitions which already have their preferred leader, or a leader with --unclean, are reported as not needing an
+election.`,
+	Args: cobra.NoArgs,
+	RunE: func(command *cobra.Command, args []string) error {
+		unclean, err := command.Flags().GetBool("unclean")
+		cobra.CheckErr(err)
+		topic, err := command.Flags().GetString("topic")
+		cobra.CheckErr(err)
+		partitions, err := command.Flags().GetInt32Slice("partition")
+		cobra.CheckErr(err)
+		if len(partitions) > 0 && topic == "" {
+			return fmt.Errorf("--partition requires --topic")
+		}
+		how := kadm.ElectPreferredReplica
+		if unclean {
+			how = kadm.ElectLiveReplica
+		}
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		ctx := context.Background()
+
+		// All the partitions of the cluster are elected with a nil set
+		var partitionsSet kadm.TopicsSet
+		if topic != "" {
+			if len(partitions) == 0 {
+				topicDetails, err := adminClient.ListTopics(ctx, topic)
+				if err != nil {
+					return fmt.Errorf("error describing topic '%s': %v", topic, err)
+				}
+				topicDetail, ok := topicDetails[topic]
+				if !ok || topicDetail.Err != nil {
+					return fmt.Errorf("topic '%s' does not exist in the cluster", topic)
+				}
+				partitions = topicDetail.Partitions.Numbers()
+			}
+			partitionsSet = make(kadm.TopicsSet)
+			partitionsSet.Add(topic, partitions...)
+		}
+
+		results, err := adminClient.ElectLeaders(ctx, how, partitionsSet)
+		if err != nil {
+			return fmt.Errorf("error electing leaders: %v", err)
+		}
+
+		var sortedResults []kadm.ElectLeadersResult
+		for _, partitionResults := range results {
+			for _, result := range partitionResults {
+				sortedResults = append(sortedResults, result)
+			}
+		}
+		slices.SortFunc(sortedResults, func(a, b kadm.ElectLeadersResult) int {
+			if diff := strings.Compare(a.Topic, b.Topic); diff != 0 {
+				return diff
+			}
+			return int(a.Partition) - int(b.Partition)
+		})
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-30s%-12s%s\n", "Topic", "Partition", "Result")
+		cobra.CheckErr(err)
+		elected := 0
+		failedToElectAnyLeader := false
+		for _, result := range sortedResults {
+			outcome := "elected"
+			switch {
+			case result.Err == nil:
+				elected++
+			case errors.Is(result.Err, kerr.ElectionNotNeeded):
+				outcome = "not needed"
+			default:
+				outcome = fmt.Sprintf("%v: %s", result.Err, result.ErrMessage)
+				failedToElectAnyLeader = true
+			}
+			_, err := fmt.Fprintf(command.OutOrStdout(), "%-30s%-12d%s\n", result.Topic, result.Partition, outcome)
+			cobra.CheckErr(err)
+		}
+		_, err = fmt.Fprintf(command.OutOrStdout(), "Elected the leader of %d partition(s)\n", elected)
+		cobra.CheckErr(err)
+
+		if failedToElectAnyLeader {
+			return fmt.Errorf("failed to elect the leader of one or more partitions")
+		}
+		return nil
+	},
+}
+
+func init() {
+	leadersCmd.Flags().Bool("preferred", false, "Elect the preferred replica of the partitions as their leader")
+	leadersCmd.Flags().Bool("unclean", false, "Elect a live replica as the leader of the partitions without leader, even when it is not in sync")
+	leadersCmd.Flags().String("topic", "", "Topic whose partitions leaders are elected")
+	leadersCmd.Flags().Int32Slice("partition", []int32{}, "Partitions of --topic whose leaders are elected, all its partitions by default")
+	leadersCmd.Flags().Bool("all", false, "Elect the leaders of all the partitions of the cluster")
+	leadersCmd.MarkFlagsOneRequired("preferred", "unclean")
+	leadersCmd.MarkFlagsMutuallyExclusive("preferred", "unclean")
+	leadersCmd.MarkFlagsOneRequired("topic", "all")
+	leadersCmd.MarkFlagsMutuallyExclusive("topic", "all")
+
+	electCmd.AddCommand(leadersCmd)
+}
@@ -0,0 +1,142 @@
+package elect
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestElectLeaders(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	_, err = adminClient.CreateTopic(ctx, 3, 1, nil, "orders")
+	assert.NoError(t, err)
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+	}{
+		{
+			name: "elect the preferred leaders of a topic",
+			args: []string{"elect", "leaders", "--preferred", "--topic", "orders"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Topic\s+Partition\s+Result\n` +
+					`orders\s+0\s+not needed\n` +
+					`orders\s+1\s+not needed\n` +
+					`orders\s+2\s+not needed\n` +
+					`Elected the leader of 0 partition\(s\)\n$`),
+			},
+		},
+		{
+			name: "elect the unclean leader of a partition",
+			args: []string{"elect", "leaders", "--unclean", "--topic", "orders", "--partition", "1"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Topic\s+Partition\s+Result\norders\s+1\s+not needed\nElected the leader of 0 partition\(s\)\n$`),
+			},
+		},
+		{
+			name: "elect the preferred leaders of all the partitions",
+			args: []string{"elect", "leaders", "--preferred", "--all"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Topic\s+Partition\s+Result\n`),
+				regexp.MustCompile(`\nElected the leader of 0 partition\(s\)\n$`),
+			},
+		},
+		{
+			name:          "elect the leader of an unknown partition",
+			args:          []string{"elect", "leaders", "--preferred", "--topic", "orders", "--partition", "7"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`\norders\s+7\s+UNKNOWN_TOPIC_OR_PARTITION`),
+				regexp.MustCompile(`Error: failed to elect the leader of one or more partitions`),
+			},
+		},
+		{
+			name:          "elect the leaders of an unknown topic",
+			args:          []string{"elect", "leaders", "--preferred", "--topic", "unknown"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: topic 'unknown' does not exist in the cluster`),
+			},
+		},
+		{
+			name:          "partition without topic",
+			args:          []string{"elect", "leaders", "--preferred", "--all", "--partition", "1"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: --partition requires --topic`),
+			},
+		},
+		{
+			name:          "no election type",
+			args:          []string{"elect", "leaders", "--all"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: at least one of the flags in the group \[preferred unclean\] is required`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+		})
+	}
+}
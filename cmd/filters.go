@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// MessageFilter selects messages by their record, or by their displayed key and value after decoding
+type MessageFilter interface {
+	Match(record *kgo.Record, key string, value string) bool
+}
+
+var whereExpression = regexp.MustCompile(`^\s*(key|value)((?:\.[^.\s=!<>\[]+|\[\d+\])*)\s*(==|!=|>=|<=|>|<)\s*(.*?)\s*$`)
+
+var pathSegment = regexp.MustCompile(`\.([^.\s=!<>\[]+)|\[(\d+)\]`)
+
+// whereFilter compares a field of the key or value, decoded as JSON, to a literal
+type whereFilter struct {
+	field    string
+	path     []any
+	operator string
+	literal  any
+}
+
+// ParseWhere parses a --where predicate: key or value, followed by a path of object fields and array indexes, an
+// operator and a JSON literal, example: value.items[0].price >= 10. Literals which are not valid JSON are strings.
+func ParseWhere(expression string) (MessageFilter, error) {
+	match := whereExpression.FindStringSubmatch(expression)
+	if match == nil || match[4] == "" {
+		return nil, fmt.Errorf("invalid --where '%s'. Expected <key|value>[.field][[index]] <==|!=|<|<=|>|>=> <literal>, example: value.customer.id == \"42\"", expression)
+	}
+	filter := &whereFilter{field: match[1], operator: match[3]}
+	for _, segment := range pathSegment.FindAllStringSubmatch(match[2], -1) {
+		if segment[1] != "" {
+			filter.path = append(filter.path, segment[1])
+			continue
+		}
+		index, err := strconv.Atoi(segment[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid index in --where '%s': %v", expression, err)
+		}
+		filter.path = append(filter.path, index)
+	}
+	literal, err := decodeJSONValue(match[4])
+	if err != nil {
+		literal = match[4]
+	}
+	filter.literal = literal
+	return filter, nil
+}
+
+func (f *whereFilter) Match(_ *kgo.Record, key string, value string) bool {
+	text := value
+	if f.field == "key" {
+		text = key
+	}
+	field, err := decodeJSONValue(text)
+	if err != nil {
+		// Data which is not JSON can only be compared as a whole, as a string
+		if len(f.path) > 0 {
+			return false
+		}
+		field = text
+	}
+	for _, segment := range f.path {
+		switch segment := segment.(type) {
+		case string:
+			object, ok := field.(map[string]any)
+			if !ok {
+				return false
+			}
+			if field, ok = object[segment]; !ok {
+				return false
+			}
+		case int:
+			array, ok := field.([]any)
+			if !ok || segment >= len(array) {
+				return false
+			}
+			field = array[segment]
+		}
+	}
+
+	comparison := compareJSONValues(field, f.literal)
+	switch f.operator {
+	case "==":
+		return comparison == 0
+	case "!=":
+		return comparison != 0
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	case ">":
+		return comparison > 0
+	case ">=":
+		return comparison >= 0
+	}
+	return false
+}
+
+func decodeJSONValue(text string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("trailing data after JSON value")
+	}
+	return value, nil
+}
+
+// compareJSONValues compares numbers numerically and anything else by its text, so that the string "42" equals the
+// number 42 and dates in ISO 8601 are ordered
+func compareJSONValues(a any, b any) int {
+	aText, bText := jsonValueText(a), jsonValueText(b)
+	aNumber, aErr := strconv.ParseFloat(aText, 64)
+	bNumber, bErr := strconv.ParseFloat(bText, 64)
+	if aErr == nil && bErr == nil && !math.IsNaN(aNumber) && !math.IsNaN(bNumber) {
+		switch {
+		case aNumber < bNumber:
+			return -1
+		case aNumber > bNumber:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(aText, bText)
+}
+
+func jsonValueText(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	}
+	data, _ := json.Marshal(value)
+	return string(bytes.TrimSpace(data))
+}
+
+// grepFilter matches a regular expression anywhere in the value
+type grepFilter struct {
+	pattern *regexp.Regexp
+}
+
+func NewGrepFilter(pattern string) (MessageFilter, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --grep '%s': %v", pattern, err)
+	}
+	return grepFilter{pattern: compiled}, nil
+}
+
+func (f grepFilter) Match(_ *kgo.Record, _ string, value string) bool {
+	return f.pattern.MatchString(value)
+}
+
+// keyFilter matches the raw key of messages
+type keyFilter struct {
+	key string
+}
+
+func NewKeyFilter(key string) MessageFilter {
+	return keyFilter{key: key}
+}
+
+func (f keyFilter) Match(record *kgo.Record, _ string, _ string) bool {
+	return record.Key != nil && string(record.Key) == f.key
+}
+
+const (
+	headerEquals    = "="
+	headerNotEquals = "!="
+	headerMatches   = "~="
+	headerPresent   = "present"
+	headerAbsent    = "absent"
+)
+
+// headerFilter matches the raw values of the headers of messages with a key
+type headerFilter struct {
+	key      string
+	operator string
+	value    string
+	pattern  *regexp.Regexp
+}
+
+// ParseHeaderFilter parses a --header predicate: key=value, key=* or key for a header which is present, !key for a
+// header which is absent, key!=value and key~=regex
+func ParseHeaderFilter(expression string) (MessageFilter, error) {
+	filter := &headerFilter{}
+	if key, ok := strings.CutPrefix(expression, "!"); ok && !strings.Contains(key, "=") {
+		filter.key, filter.operator = key, headerAbsent
+	} else if index := strings.Index(expression, "="); index < 0 {
+		filter.key, filter.operator = expression, headerPresent
+	} else {
+		filter.key, filter.operator, filter.value = expression[:index], headerEquals, expression[index+1:]
+		if key, ok := strings.CutSuffix(filter.key, "!"); ok {
+			filter.key, filter.operator = key, headerNotEquals
+		} else if key, ok := strings.CutSuffix(filter.key, "~"); ok {
+			filter.key, filter.operator = key, headerMatches
+		} else if filter.value == "*" {
+			filter.operator = headerPresent
+		}
+	}
+	if filter.key == "" {
+		return nil, fmt.Errorf("invalid --header '%s'. Expected key=value, key!=value, key~=regex, key=*, key or !key", expression)
+	}
+	if filter.operator == headerMatches {
+		pattern, err := regexp.Compile(filter.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --header '%s': %v", expression, err)
+		}
+		filter.pattern = pattern
+	}
+	return filter, nil
+}
+
+// Match tells whether a header with the key matches, or for != and absent that none does
+func (f *headerFilter) Match(record *kgo.Record, _ string, _ string) bool {
+	for _, header := range record.Headers {
+		if header.Key != f.key {
+			continue
+		}
+		switch f.operator {
+		case headerPresent:
+			return true
+		case headerAbsent:
+			return false
+		case headerEquals:
+			if string(header.Value) == f.value {
+				return true
+			}
+		case headerNotEquals:
+			if string(header.Value) == f.value {
+				return false
+			}
+		case headerMatches:
+			if f.pattern.Match(header.Value) {
+				return true
+			}
+		}
+	}
+	return f.operator == headerAbsent || f.operator == headerNotEquals
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestParseWhere(t *testing.T) {
+	order := `{"id": 7, "customer": {"id": 42, "name": "Alice"}, "items": [{"sku": "book", "quantity": 2}], "paid": true, "date": "2025-03-01"}`
+
+	tests := []struct {
+		expression    string
+		key           string
+		value         string
+		expected      bool
+		expectedError bool
+	}{
+		{expression: `value.customer.id == "42"`, value: order, expected: true},
+		{expression: `value.customer.id == 42`, value: order, expected: true},
+		{expression: `value.customer.id == 42.0`, value: order, expected: true},
+		{expression: `value.customer.id != 42`, value: order, expected: false},
+		{expression: `value.customer.name == Alice`, value: order, expected: true},
+		{expression: `value.customer.name == "Bob"`, value: order, expected: false},
+		{expression: `value.items[0].quantity > 1`, value: order, expected: true},
+		{expression: `value.items[0].quantity >= 3`, value: order, expected: false},
+		{expression: `value.items[1].quantity < 3`, value: order, expected: false},
+		{expression: `value.paid == true`, value: order, expected: true},
+		{expression: `value.date < "2025-04-01"`, value: order, expected: true},
+		{expression: `value.date <= "2025-02-28"`, value: order, expected: false},
+		{expression: `value.missing == null`, value: order, expected: false},
+		{expression: `value.missing != 1`, value: order, expected: false},
+		{expression: `value.customer.id.first == 1`, value: order, expected: false},
+		{expression: `value == "plain text"`, value: "plain text", expected: true},
+		{expression: `value.id == 1`, value: "plain text", expected: false},
+		{expression: `key == order-1`, key: "order-1", value: order, expected: true},
+		{expression: `key == 10`, key: "10", value: order, expected: true},
+		{expression: `value.customer == {"id":42,"name":"Alice"}`, value: order, expected: true},
+		{expression: `value.customer.id`, expectedError: true},
+		{expression: `headers.id == 1`, expectedError: true},
+		{expression: `value.id ==`, expectedError: true},
+		{expression: `value.id = 1`, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			filter, err := ParseWhere(tt.expression)
+			if tt.expectedError {
+				assert.ErrorContains(t, err, "invalid --where")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, filter.Match(&kgo.Record{}, tt.key, tt.value))
+		})
+	}
+}
+
+func TestNewGrepFilter(t *testing.T) {
+	filter, err := NewGrepFilter(`order-(17|18)\b`)
+	assert.NoError(t, err)
+	assert.True(t, filter.Match(&kgo.Record{}, "", `{"id": "order-17"}`))
+	assert.False(t, filter.Match(&kgo.Record{}, "order-17", `{"id": "order-170"}`))
+
+	_, err = NewGrepFilter(`order-(`)
+	assert.ErrorContains(t, err, "invalid --grep 'order-('")
+}
+
+func TestParseHeaderFilter(t *testing.T) {
+	headers := []kgo.RecordHeader{
+		{Key: "source", Value: []byte("web")},
+		{Key: "trace-id", Value: []byte("abc-123")},
+		{Key: "tag", Value: []byte("a")},
+		{Key: "tag", Value: []byte("b")},
+		{Key: "empty", Value: nil},
+	}
+
+	tests := []struct {
+		expression    string
+		headers       []kgo.RecordHeader
+		expected      bool
+		expectedError bool
+	}{
+		{expression: "source=web", headers: headers, expected: true},
+		{expression: "source=mobile", headers: headers, expected: false},
+		{expression: "source=web", headers: nil, expected: false},
+		{expression: "source=*", headers: headers, expected: true},
+		{expression: "source=*", headers: nil, expected: false},
+		{expression: "source", headers: headers, expected: true},
+		{expression: "empty", headers: headers, expected: true},
+		{expression: "empty=", headers: headers, expected: true},
+		{expression: "missing", headers: headers, expected: false},
+		{expression: "!missing", headers: headers, expected: true},
+		{expression: "!source", headers: headers, expected: false},
+		{expression: "!source", headers: nil, expected: true},
+		{expression: "source!=mobile", headers: headers, expected: true},
+		{expression: "source!=web", headers: headers, expected: false},
+		{expression: "source!=web", headers: nil, expected: true},
+		{expression: "tag=b", headers: headers, expected: true},
+		{expression: "tag!=b", headers: headers, expected: false},
+		{expression: "trace-id~=^abc-[0-9]+$", headers: headers, expected: true},
+		{expression: "trace-id~=^def", headers: headers, expected: false},
+		{expression: "url=a=b", headers: []kgo.RecordHeader{{Key: "url", Value: []byte("a=b")}}, expected: true},
+		{expression: "=web", expectedError: true},
+		{expression: "!", expectedError: true},
+		{expression: "~=web", expectedError: true},
+		{expression: "trace-id~=(", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			filter, err := ParseHeaderFilter(tt.expression)
+			if tt.expectedError {
+				assert.ErrorContains(t, err, "invalid --header '"+tt.expression+"'")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, filter.Match(&kgo.Record{Headers: tt.headers}, "", ""))
+		})
+	}
+}
+
+func TestNewKeyFilter(t *testing.T) {
+	filter := NewKeyFilter("order-1")
+	assert.True(t, filter.Match(&kgo.Record{Key: []byte("order-1")}, "", ""))
+	assert.False(t, filter.Match(&kgo.Record{Key: []byte("order-2")}, "", ""))
+	assert.False(t, filter.Match(&kgo.Record{Key: nil}, "", ""))
+}
@@ -0,0 +1,55 @@
+package get
+
+import (
+	"context"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var aclsCmd = &cobra.Command{
+	Use:   "acls [--principal <principal>] [--host <host>] [--resource-type <type>] [--resource-name <name>] [--pattern-type <pattern>] [--operation <operation>] [--permission <permission>]",
+	Short: "Display ACLs of the current cluster",
+	Long: `Display ACLs of the current cluster, all of them or those matching the filters.
+
+Unset filters match anything. Principals without a type are users, alice being User:alice:
+- kacao get acls
+- kacao get acls --principal alice
+- kacao get acls --resource-type topic --resource-name orders --operation read,write
+
+With --pattern-type match, the ACLs which apply to a resource are displayed: its literal ACLs, the prefixed ACLs whose
+prefix matches its name and the wildcard ACLs of its type:
+- kacao get acls --resource-type topic --resource-name orders --pattern-type match`,
+	Args: cobra.NoArgs,
+	RunE: func(command *cobra.Command, args []string) error {
+		spec, err := cmd.GetACLFlags(command, false)
+		if err != nil {
+			return err
+		}
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		consumerGroup, err := cmd.GetConsumerGroup()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(
+			kgo.SeedBrokers(boostrapServers...),
+			kgo.ConsumerGroup(consumerGroup),
+		)
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		acls, err := cmd.DescribeACLs(context.Background(), adminClient, spec.Builder())
+		if err != nil {
+			return err
+		}
+		return cmd.PrintACLs(command.OutOrStdout(), acls)
+	},
+}
+
+func init() {
+	cmd.AddACLFlags(aclsCmd, false)
+	getCmd.AddCommand(aclsCmd)
+}
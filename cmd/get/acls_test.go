@@ -0,0 +1,156 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestGetACLs(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+		testcontainers.WithEnv(map[string]string{
+			"KAFKA_AUTHORIZER_CLASS_NAME": "org.apache.kafka.metadata.authorizer.StandardAuthorizer",
+			"KAFKA_SUPER_USERS":           "User:ANONYMOUS",
+		}),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	for _, builder := range []*kadm.ACLBuilder{
+		kadm.NewACLs().Allow("User:alice").Topics("orders").Operations(kadm.OpRead, kadm.OpDescribe).ResourcePatternType(kadm.ACLPatternLiteral),
+		kadm.NewACLs().Allow("User:bob").Topics("billing-").Operations(kadm.OpWrite).ResourcePatternType(kadm.ACLPatternPrefixed),
+		kadm.NewACLs().Deny("User:alice").DenyHosts("10.0.0.1").Groups("billing").Operations(kadm.OpRead).ResourcePatternType(kadm.ACLPatternLiteral),
+	} {
+		results, err := adminClient.CreateACLs(ctx, builder)
+		assert.NoError(t, err)
+		for _, result := range results {
+			assert.NoError(t, result.Err)
+		}
+	}
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+		notExpected      []*regexp.Regexp
+	}{
+		{
+			name: "get all acls",
+			args: []string{"get", "acls"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Principal\s+Host\s+Resource type\s+Resource name\s+Pattern\s+Operation\s+Permission\s+\n` +
+					`User:alice\s+\*\s+topic\s+orders\s+literal\s+read\s+allow\s+\n` +
+					`User:alice\s+\*\s+topic\s+orders\s+literal\s+describe\s+allow\s+\n` +
+					`User:bob\s+\*\s+topic\s+billing-\s+prefixed\s+write\s+allow\s+\n` +
+					`User:alice\s+10.0.0.1\s+group\s+billing\s+literal\s+read\s+deny\s+\n$`),
+			},
+		},
+		{
+			name: "get acls of a principal without type",
+			args: []string{"get", "acls", "--principal", "alice"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`User:alice\s+\*\s+topic\s+orders`),
+				regexp.MustCompile(`User:alice\s+10.0.0.1\s+group\s+billing`),
+			},
+			notExpected: []*regexp.Regexp{regexp.MustCompile(`User:bob`)},
+		},
+		{
+			name: "get acls by resource and operation",
+			args: []string{"get", "acls", "--resource-type", "topic", "--resource-name", "orders", "--operation", "describe"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`\nUser:alice\s+\*\s+topic\s+orders\s+literal\s+describe\s+allow\s+\n$`),
+			},
+		},
+		{
+			name: "get acls by permission and host",
+			args: []string{"get", "acls", "--permission", "deny", "--host", "10.0.0.1"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`\nUser:alice\s+10.0.0.1\s+group\s+billing\s+literal\s+read\s+deny\s+\n$`),
+			},
+		},
+		{
+			name: "get acls which apply to a resource",
+			args: []string{"get", "acls", "--resource-type", "topic", "--resource-name", "billing-invoices", "--pattern-type", "match"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`\nUser:bob\s+\*\s+topic\s+billing-\s+prefixed\s+write\s+allow\s+\n$`),
+			},
+		},
+		{
+			name: "get acls matching nothing",
+			args: []string{"get", "acls", "--principal", "User:carol"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Principal\s+Host\s+Resource type\s+Resource name\s+Pattern\s+Operation\s+Permission\s+\n$`),
+			},
+		},
+		{
+			name:          "invalid operation",
+			args:          []string{"get", "acls", "--operation", "publish"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid --operation 'publish'`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+			for _, pattern := range tt.notExpected {
+				assert.NotRegexp(t, pattern, output)
+			}
+		})
+	}
+}
@@ -12,21 +12,61 @@ import (
 	"slices"
 	"strings"
 	"syscall"
+	"time"
 )
 
 var messagesCmd = &cobra.Command{
-	Use:   "messages <topic_name> [--limit <limit>] [--key key] [--header <key=value>]",
+	Use:   "messages <topic_name> [--limit <limit>] [--key key] [--header <key=value>] [--where <predicate>] [--grep <regex>] [--isolation <level>] [--show-control-records]",
 	Short: "Get messages from a topic",
 	Long: `Get messages from a topic
 
 This command will retrieve {limit} messages from each partition of the specified topic. Then filter for the {limit} most recent messages.
 
-If you are filtering by header, you may get less than {limit} messages since the filter is applied after the messages are retrieved.
-If multiple headers are provided, all must match. Putting * as the value will match any value.
+If you are filtering, you may get less than {limit} messages since the filters are applied after the messages are retrieved.
+All filters must match. Headers are filtered with:
+- key=value: a header has the value, key=* or key: the header is present, whatever its value
+- key!=value: no header has the value, including when the header is absent
+- key~=regex: the value of a header matches the regular expression
+- !key: the header is absent
 
 Example:
 - kacao get messages <topic_name> --limit 10 --key my-key --header key1=value1 --header key2=*
 Will retrieve 10 messages from each partition of the topic <topic_name> and filter for messages that have for key "my-key", and headers with key1=value1 and key2 having any value.
+
+Messages are also filtered by their content, after being decoded, with --where and --grep:
+- --where compares a field of the key or value, parsed as JSON, to a JSON literal with ==, !=, <, <=, > or >=. Fields
+  are accessed by name and array elements by index, and literals which are not valid JSON are strings. Keys and values
+  which are not JSON are compared as a whole, as text. All --where predicates must match.
+- --grep matches a regular expression anywhere in the displayed value.
+- kacao get messages orders --limit 1000 --value-decoder schema-registry --where 'value.customer.id == "42"' --where 'value.items[0].quantity > 1'
+- kacao get messages orders --grep 'order-(17|18)'
+
+Null keys and values (tombstones) are displayed as <null>, empty ones are left blank.
+Binary keys, values and header values can be displayed with --key-encoding, --value-encoding and --header-encoding:
+text (the default), hex, base64 or auto, which uses text for printable data and base64 otherwise.
+
+Keys and values serialized with a schema registry (Avro, Protobuf or JSON Schema, in the Confluent wire format) are
+decoded to JSON with --key-decoder schema-registry and --value-decoder schema-registry. The schema registry is set on
+the cluster with 'kacao config set-cluster <name> --bootstrap-servers <servers> --schema-registry <url>'. Data which is
+not in the wire format is displayed with the encoding flags.
+
+Raw Protobuf values are decoded to JSON without a schema registry with --proto-descriptor-set and --proto-message,
+the descriptor set being written by 'protoc --include_imports --descriptor_set_out=shop.pb shop.proto'. Topics can
+also be mapped to their message in the cluster configuration, to be decoded without flags:
+- kacao config set-cluster <name> --bootstrap-servers <servers> --proto-descriptor-set shop.pb --proto-topic orders=shop.Order
+
+Other decoders are selected with --key-decoder and --value-decoder: text, json (compacted), avro:<schema.avsc> for raw
+Avro data, protobuf:<descriptor_set.pb>:<message> for raw Protobuf data, and exec:<command>, which runs a shell command
+with the data on its standard input and displays its standard output. The command is started once per key and per
+value, and stopped after 10 seconds, so it suits small numbers of messages. The decoders of topics are set in the
+cluster configuration, and used when the flags are not set:
+- kacao config set-cluster <name> --bootstrap-servers <servers> --topic-value-decoder orders=avro:order.avsc --topic-key-decoder audit=exec:audit-decode
+
+Messages of transactions are read with --isolation read_uncommitted by default, and messages of aborted transactions
+are then displayed like committed ones. With --isolation read_committed, messages of aborted transactions are hidden and
+only messages before the last stable offset are retrieved, like a transactional consumer would see them.
+--show-control-records also displays the commit and abort markers of transactions, with the id of the producer that
+wrote them: the messages of an aborted transaction are the ones of its producer id before its ABORT marker.
 `,
 	Args: cobra.ExactArgs(1),
 	RunE: func(command *cobra.Command, args []string) error {
@@ -37,31 +77,37 @@ Will retrieve 10 messages from each partition of the topic <topic_name> and filt
 
 		limit, err := command.Flags().GetInt64("limit")
 		cobra.CheckErr(err)
-		headers, err := command.Flags().GetStringArray("header")
-		cobra.CheckErr(err)
-		headersMap := make(map[string]string)
-		for _, header := range headers {
-			parts := strings.Split(header, "=")
-			if len(parts) != 2 {
-				return fmt.Errorf("Invalid header format: %s. Expected key=value.\n", header)
-			}
-			headersMap[parts[0]] = parts[1]
+		messageFilters, err := getMessageFilters(command)
+		if err != nil {
+			return err
+		}
+		encodings, err := cmd.GetEncodingFlags(command)
+		if err != nil {
+			return err
 		}
-		keyFilter, err := command.Flags().GetString("key")
-		cobra.CheckErr(err)
 
-		cl, err := kgo.NewClient(
+		isolation, err := cmd.GetIsolationFlags(command)
+		if err != nil {
+			return err
+		}
+
+		opts := append([]kgo.Opt{
 			kgo.SeedBrokers(boostrapServers...),
 			kgo.ConsumerGroup(consumerGroup),
 			kgo.ConsumeTopics(args[0]),
-		)
+			kgo.DisableAutoCommit(),
+			// Control records take up offsets, they are needed to know when a partition was read up to its end
+			kgo.KeepControlRecords(),
+		}, isolation.ClientOpts()...)
+		cl, err := kgo.NewClient(opts...)
 		cobra.CheckErr(err)
 		adminClient := kadm.NewClient(cl)
 		defer cl.Close()
 		defer adminClient.Close()
-		ctx := context.Background()
+		ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stopSignals()
 
-		committedListedOffsets, err := adminClient.ListEndOffsets(ctx, args[0])
+		committedListedOffsets, err := isolation.ListEndOffsets(ctx, adminClient, args[0])
 		cobra.CheckErr(err)
 		for _, listedOffset := range committedListedOffsets[args[0]] {
 			if listedOffset.Err != nil {
@@ -69,13 +115,8 @@ Will retrieve 10 messages from each partition of the topic <topic_name> and filt
 			}
 		}
 
-		var limitsByPartition = make(map[int32]int64)
-		var counterByPartition = make(map[int32]int64)
-		for _, listedOffset := range committedListedOffsets[args[0]] {
-			limitsByPartition[listedOffset.Partition] = 10
-			counterByPartition[listedOffset.Partition] = 0
-		}
-
+		// End offsets of the partitions which were not read up to their end yet
+		var remainingPartitions = make(map[int32]int64)
 		var newOffsets kadm.Offsets = make(map[string]map[int32]kadm.Offset)
 		newOffsets[args[0]] = make(map[int32]kadm.Offset)
 		for _, listedOffset := range committedListedOffsets[args[0]] {
@@ -83,7 +124,9 @@ Will retrieve 10 messages from each partition of the topic <topic_name> and filt
 
 			if offsetValue < 0 {
 				offsetValue = 0
-				limitsByPartition[listedOffset.Partition] = listedOffset.Offset
+			}
+			if listedOffset.Offset > offsetValue {
+				remainingPartitions[listedOffset.Partition] = listedOffset.Offset
 			}
 
 			newOffsets[args[0]][listedOffset.Partition] = kadm.Offset{
@@ -97,45 +140,34 @@ Will retrieve 10 messages from each partition of the topic <topic_name> and filt
 		err = adminClient.CommitAllOffsets(ctx, consumerGroup, newOffsets)
 		cobra.CheckErr(err)
 
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-		go func() {
-			<-sigChan
-			_, err := fmt.Fprintf(command.OutOrStdout(), "Closing client...")
-			cl.Close()
-			adminClient.Close()
-			cobra.CheckErr(err)
-			fmt.Println("Client closed")
-		}()
-
 		records := make([]kgo.Record, 0)
 
-		for {
-			fetches := cl.PollFetches(ctx)
-			if errs := fetches.Errors(); len(errs) > 0 {
-				panic(fmt.Sprint(errs))
-			}
-
-			iter := fetches.RecordIter()
-
-			for !iter.Done() {
-				record := *iter.Next()
-				if counterByPartition[record.Partition] >= limitsByPartition[record.Partition] {
-					continue
+		if len(remainingPartitions) > 0 {
+			err = cmd.PollRecords(ctx, cl, command.ErrOrStderr(), func(record *kgo.Record) bool {
+				endOffset, ok := remainingPartitions[record.Partition]
+				if !ok || record.Offset >= endOffset {
+					delete(remainingPartitions, record.Partition)
+					return len(remainingPartitions) > 0
 				}
-				counterByPartition[record.Partition]++
-				records = append(records, record)
-			}
-
-			allCountersReached := true
-			for partition, counter := range counterByPartition {
-				if counter < limitsByPartition[partition] {
-					allCountersReached = false
-					break
+				if !record.Attrs.IsControl() || isolation.ShowControlRecords {
+					records = append(records, *record)
 				}
+				if record.Offset+1 >= endOffset {
+					delete(remainingPartitions, record.Partition)
+				}
+				return len(remainingPartitions) > 0
+			})
+
+			commitCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if commitErr := cl.CommitUncommittedOffsets(commitCtx); commitErr != nil {
+				return fmt.Errorf("error committing offsets of consumer group '%s': %v", consumerGroup, commitErr)
 			}
-			if allCountersReached {
-				break
+			if ctx.Err() != nil {
+				return cmd.ErrInterrupted
+			}
+			if err != nil {
+				return err
 			}
 		}
 
@@ -161,55 +193,46 @@ Will retrieve 10 messages from each partition of the topic <topic_name> and filt
 			records = records[:limit]
 		}
 
-		if len(keyFilter) > 0 {
-			var filteredRecords []kgo.Record
-			for _, record := range records {
-				if string(record.Key) == keyFilter {
-					filteredRecords = append(filteredRecords, record)
-				}
-			}
-			records = filteredRecords
+		type message struct {
+			record *kgo.Record
+			key    string
+			value  string
 		}
-
-		if len(headers) > 0 || len(keyFilter) > 0 {
-			var filteredRecords []kgo.Record
-			for _, record := range records {
-				match := false
-				for _, recordHeader := range record.Headers {
-					if _, ok := headersMap[recordHeader.Key]; ok {
-						if headersMap[recordHeader.Key] != "*" && string(recordHeader.Value) != headersMap[recordHeader.Key] {
-							match = false
-							break
-						} else {
-							match = true
-						}
-					}
-				}
-				if match {
-					filteredRecords = append(filteredRecords, record)
-				}
+		var messages []message
+		for i := range records {
+			record := &records[i]
+			key, err := encodings.FormatKey(record)
+			if err != nil {
+				return fmt.Errorf("error decoding key of message at offset %d of partition %d: %v", record.Offset, record.Partition, err)
+			}
+			value, err := encodings.FormatValue(record)
+			if err != nil {
+				return fmt.Errorf("error decoding value of message at offset %d of partition %d: %v", record.Offset, record.Partition, err)
+			}
+			if !slices.ContainsFunc(messageFilters, func(filter cmd.MessageFilter) bool { return !filter.Match(record, key, value) }) {
+				messages = append(messages, message{record: record, key: key, value: value})
 			}
-			records = filteredRecords
 		}
 
 		_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%-25s%-25s%-25s%-25s%-25s\n", "Topic", "Partition", "Offset", "Key", "Value", "Headers")
 
-		for _, record := range records {
+		for _, message := range messages {
+			record := message.record
 			if len(record.Headers) == 0 {
 				_, err := fmt.Fprintf(command.OutOrStdout(), "%-25s%-25d%-25d%-25s%-25s\n",
-					record.Topic, record.Partition, record.Offset, string(record.Key), string(record.Value))
+					record.Topic, record.Partition, record.Offset, message.key, message.value)
 				cobra.CheckErr(err)
 				continue
 			}
 
 			var headers []string
 			for _, header := range record.Headers {
-				headers = append(headers, fmt.Sprintf("%s: %s", header.Key, string(header.Value)))
+				headers = append(headers, fmt.Sprintf("%s: %s", header.Key, cmd.FormatRecordBytes(header.Value, encodings.Header)))
 			}
 			headersString := strings.Join(headers, ", ")
 
-			_, err := fmt.Fprintf(command.OutOrStdout(), "%-25s%-25d%-25d%-25s%-25s%-25s\n",
-				record.Topic, record.Partition, record.Offset, string(record.Key), string(record.Value), headersString)
+			_, err = fmt.Fprintf(command.OutOrStdout(), "%-25s%-25d%-25d%-25s%-25s%-25s\n",
+				record.Topic, record.Partition, record.Offset, message.key, message.value, headersString)
 			cobra.CheckErr(err)
 		}
 
@@ -217,10 +240,53 @@ Will retrieve 10 messages from each partition of the topic <topic_name> and filt
 	},
 }
 
+// getMessageFilters returns the filters of --key, --header, --where and --grep, which must all match
+func getMessageFilters(command *cobra.Command) ([]cmd.MessageFilter, error) {
+	key, err := command.Flags().GetString("key")
+	cobra.CheckErr(err)
+	headers, err := command.Flags().GetStringArray("header")
+	cobra.CheckErr(err)
+	whereExpressions, err := command.Flags().GetStringArray("where")
+	cobra.CheckErr(err)
+	grep, err := command.Flags().GetString("grep")
+	cobra.CheckErr(err)
+
+	var filters []cmd.MessageFilter
+	if key != "" {
+		filters = append(filters, cmd.NewKeyFilter(key))
+	}
+	for _, header := range headers {
+		filter, err := cmd.ParseHeaderFilter(header)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	for _, expression := range whereExpressions {
+		filter, err := cmd.ParseWhere(expression)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if grep != "" {
+		filter, err := cmd.NewGrepFilter(grep)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
 func init() {
 	messagesCmd.Flags().Int64P("limit", "l", 10, "Limit the number of messages to get.")
 	messagesCmd.Flags().StringP("key", "k", "", "Filter messages by key, example: --key value")
-	messagesCmd.Flags().StringArrayP("header", "H", []string{}, "Filter messages by header, example: --header key=value.")
+	messagesCmd.Flags().StringArrayP("header", "H", []string{}, "Filter messages by header with key=value, key!=value, key~=regex, key=*, key or !key, example: --header source=web")
+	messagesCmd.Flags().StringArrayP("where", "w", []string{}, "Filter messages by a field of their decoded key or value, example: --where 'value.customer.id == \"42\"'")
+	messagesCmd.Flags().StringP("grep", "g", "", "Filter messages whose decoded value matches a regular expression, example: --grep 'order-(17|18)'")
+	cmd.AddEncodingFlags(messagesCmd)
+	cmd.AddIsolationFlags(messagesCmd)
 
 	getCmd.AddCommand(messagesCmd)
 }
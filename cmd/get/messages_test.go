@@ -9,6 +9,9 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/kafka"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+	"google.golang.org/protobuf/encoding/protowire"
 	"regexp"
 	"testing"
 	"time"
@@ -43,10 +46,22 @@ func TestGetMessages(t *testing.T) {
 	defer cl.Close()
 	defer adminClient.Close()
 
+	// Basic authentication of the user "user" with the password "password"
+	registry := srfake.New(srfake.WithAuth("Basic dXNlcjpwYXNzd29yZA=="))
+	defer registry.Close()
+	registry.SeedSchema("topic11-key", 1, 1, sr.Schema{Schema: `{"type": "long"}`})
+	registry.SeedSchema("topic11-value", 1, 2, sr.Schema{Schema: `{"type": "record", "name": "Order", "fields": [{"name": "item", "type": "string"}]}`})
+
 	testConfig := test_helpers.TestConfig{
 		Clusters: map[string]map[string]interface{}{
 			"test-cluster": {
-				"bootstrap-servers": brokers,
+				"bootstrap-servers":        brokers,
+				"schema-registry":          registry.URL(),
+				"schema-registry-username": "user",
+				"schema-registry-password": "password",
+				"topic-decoders": []map[string]string{
+					{"topic": "topic13", "key": "text", "value": "exec:tr a-z A-Z"},
+				},
 			},
 		},
 		Contexts: map[string]map[string]interface{}{
@@ -63,9 +78,13 @@ func TestGetMessages(t *testing.T) {
 		produceMessages  map[string][]string
 		headers          []map[string]string
 		keys             []string
+		produceRecords   []*kgo.Record
 		getArgs          []string
 		expectedPatterns []*regexp.Regexp
 		expectedError    bool
+		// Produced to the first created topic, the aborted transaction first
+		abortedTransaction   []string
+		committedTransaction []string
 	}{
 		{
 			name:         "existent topic with messages",
@@ -146,6 +165,226 @@ func TestGetMessages(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name:         "null key and tombstone are displayed as null",
+			createTopics: []string{"topic6"},
+			produceRecords: []*kgo.Record{
+				{Topic: "topic6", Key: []byte(""), Value: []byte("empty key")},
+				{Topic: "topic6", Key: nil, Value: []byte("null key")},
+				{Topic: "topic6", Key: []byte("deleted-key"), Value: nil},
+			},
+			getArgs: []string{"get", "messages", "topic6"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`topic6\s+0\s+2\s+deleted-key\s+<null>\s+topic6\s+0\s+1\s+<null>\s+null key\s+topic6\s+0\s+0\s+empty key`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "binary values with encodings",
+			createTopics: []string{"topic7"},
+			produceRecords: []*kgo.Record{
+				{Topic: "topic7", Key: []byte{0xca, 0xfe}, Value: []byte{0x00, 0x01, 0x1b}, Headers: []kgo.RecordHeader{{Key: "header-key", Value: []byte("printable")}}},
+			},
+			getArgs: []string{"get", "messages", "topic7", "--key-encoding", "hex", "--value-encoding", "auto", "--header-encoding", "auto"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`topic7\s+0\s+0\s+cafe\s+AAEb\s+header-key: printable`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "invalid encoding",
+			createTopics: []string{"topic8"},
+			getArgs:      []string{"get", "messages", "topic8", "--value-encoding", "utf16"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid --value-encoding 'utf16'. Use one of: text, hex, base64, auto`),
+			},
+			expectedError: true,
+		},
+		{
+			name:         "avro keys and values with the schema registry",
+			createTopics: []string{"topic11"},
+			produceRecords: []*kgo.Record{
+				{
+					Topic: "topic11",
+					Key:   test_helpers.AvroWireFormat(t, 1, `{"type": "long"}`, int64(7)),
+					Value: test_helpers.AvroWireFormat(t, 2, `{"type": "record", "name": "Order", "fields": [{"name": "item", "type": "string"}]}`, map[string]any{"item": "book"}),
+				},
+			},
+			getArgs: []string{"get", "messages", "topic11", "--key-decoder", "schema-registry", "--value-decoder", "schema-registry"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`topic11\s+0\s+0\s+7\s+\{"item":"book"\}`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "protobuf values with a descriptor set",
+			createTopics: []string{"topic12"},
+			produceRecords: []*kgo.Record{
+				{Topic: "topic12", Key: []byte("order-1"), Value: protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), "book")},
+			},
+			getArgs: []string{"get", "messages", "topic12", "--proto-descriptor-set", test_helpers.ProtoDescriptorSet(t, `syntax = "proto3"; package shop; message Order { string item = 1; }`), "--proto-message", "shop.Order"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`topic12\s+0\s+0\s+order-1\s+\{"item":"book"\}`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "values with the exec decoder of the topic",
+			createTopics: []string{"topic13"},
+			produceRecords: []*kgo.Record{
+				{Topic: "topic13", Key: []byte("order-1"), Value: []byte("book")},
+			},
+			getArgs: []string{"get", "messages", "topic13"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`topic13\s+0\s+0\s+order-1\s+BOOK`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "json values with the json decoder",
+			createTopics: []string{"topic14"},
+			produceRecords: []*kgo.Record{
+				{Topic: "topic14", Key: []byte("order-1"), Value: []byte(`{ "item": "book" }`)},
+			},
+			getArgs: []string{"get", "messages", "topic14", "--value-decoder", "json"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`topic14\s+0\s+0\s+order-1\s+\{"item":"book"\}`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "invalid decoder",
+			createTopics: []string{"topic15"},
+			getArgs:      []string{"get", "messages", "topic15", "--value-decoder", "avro"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid --value-decoder 'avro': missing schema file. Use avro:<schema.avsc>`),
+			},
+			expectedError: true,
+		},
+		{
+			name:         "filter by json field and regex",
+			createTopics: []string{"topic16"},
+			produceRecords: []*kgo.Record{
+				{Topic: "topic16", Key: []byte("order-1"), Value: []byte(`{"customer": {"id": 42}, "items": [{"sku": "book"}]}`)},
+				{Topic: "topic16", Key: []byte("order-2"), Value: []byte(`{"customer": {"id": 43}, "items": [{"sku": "book"}]}`)},
+				{Topic: "topic16", Key: []byte("order-3"), Value: []byte(`{"customer": {"id": 42}, "items": [{"sku": "pen"}]}`)},
+				{Topic: "topic16", Key: []byte("order-4"), Value: []byte("not json")},
+			},
+			getArgs: []string{"get", "messages", "topic16", "--where", `value.customer.id == "42"`, "--grep", `"sku":\s*"book"`},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Headers\s+topic16\s+0\s+0\s+order-1\s+\{"customer": \{"id": 42\}, "items": \[\{"sku": "book"\}\]\}\s+$`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "filter by key and json field",
+			createTopics: []string{"topic17"},
+			produceRecords: []*kgo.Record{
+				{Topic: "topic17", Key: []byte("order-1"), Value: []byte(`{"status": "paid"}`), Headers: []kgo.RecordHeader{{Key: "source", Value: []byte("web")}}},
+				{Topic: "topic17", Key: []byte("order-1"), Value: []byte(`{"status": "shipped"}`), Headers: []kgo.RecordHeader{{Key: "source", Value: []byte("web")}}},
+			},
+			getArgs: []string{"get", "messages", "topic17", "--key", "order-1", "--header", "source=web", "--where", "value.status != paid"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Headers\s+topic17\s+0\s+1\s+order-1\s+\{"status": "shipped"\}\s+source: web\s+$`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "invalid where predicate",
+			createTopics: []string{"topic18"},
+			getArgs:      []string{"get", "messages", "topic18", "--where", "value.status"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid --where 'value.status'`),
+			},
+			expectedError: true,
+		},
+		{
+			name:         "filter by key keeps messages without headers",
+			createTopics: []string{"topic19"},
+			produceRecords: []*kgo.Record{
+				{Topic: "topic19", Key: []byte("order-1"), Value: []byte("created")},
+				{Topic: "topic19", Key: []byte("order-2"), Value: []byte("created")},
+			},
+			getArgs: []string{"get", "messages", "topic19", "--key", "order-1"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Headers\s+topic19\s+0\s+0\s+order-1\s+created\s+$`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "filter by headers requires every header to match",
+			createTopics: []string{"topic20"},
+			produceRecords: []*kgo.Record{
+				{Topic: "topic20", Key: []byte("order-1"), Value: []byte("a"), Headers: []kgo.RecordHeader{{Key: "source", Value: []byte("web")}, {Key: "region", Value: []byte("eu")}}},
+				{Topic: "topic20", Key: []byte("order-2"), Value: []byte("b"), Headers: []kgo.RecordHeader{{Key: "source", Value: []byte("web")}, {Key: "region", Value: []byte("us")}}},
+				{Topic: "topic20", Key: []byte("order-3"), Value: []byte("c"), Headers: []kgo.RecordHeader{{Key: "region", Value: []byte("eu")}}},
+			},
+			getArgs: []string{"get", "messages", "topic20", "--header", "source=web", "--header", "region=eu"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Headers\s+topic20\s+0\s+0\s+order-1\s+a\s+source: web, region: eu\s+$`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "filter by header operators",
+			createTopics: []string{"topic21"},
+			produceRecords: []*kgo.Record{
+				{Topic: "topic21", Key: []byte("order-1"), Value: []byte("a"), Headers: []kgo.RecordHeader{{Key: "trace-id", Value: []byte("abc-1")}, {Key: "source", Value: []byte("web")}}},
+				{Topic: "topic21", Key: []byte("order-2"), Value: []byte("b"), Headers: []kgo.RecordHeader{{Key: "trace-id", Value: []byte("abc-2")}, {Key: "source", Value: []byte("batch")}}},
+				{Topic: "topic21", Key: []byte("order-3"), Value: []byte("c"), Headers: []kgo.RecordHeader{{Key: "trace-id", Value: []byte("def-3")}}},
+				{Topic: "topic21", Key: []byte("order-4"), Value: []byte("d"), Headers: []kgo.RecordHeader{{Key: "trace-id", Value: []byte("abc-4")}, {Key: "retry", Value: []byte("1")}}},
+				{Topic: "topic21", Key: []byte("order-5"), Value: []byte("e")},
+			},
+			getArgs: []string{"get", "messages", "topic21", "--header", "trace-id~=^abc-", "--header", "source!=batch", "--header", "!retry"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Headers\s+topic21\s+0\s+0\s+order-1\s+a\s+trace-id: abc-1, source: web\s+$`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "filter by header presence",
+			createTopics: []string{"topic22"},
+			produceRecords: []*kgo.Record{
+				{Topic: "topic22", Key: []byte("order-1"), Value: []byte("a"), Headers: []kgo.RecordHeader{{Key: "retry", Value: []byte("1")}}},
+				{Topic: "topic22", Key: []byte("order-2"), Value: []byte("b")},
+			},
+			getArgs: []string{"get", "messages", "topic22", "--header", "retry"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Headers\s+topic22\s+0\s+0\s+order-1\s+a\s+retry: 1\s+$`),
+			},
+			expectedError: false,
+		},
+		{
+			name:         "invalid header filter",
+			createTopics: []string{"topic23"},
+			getArgs:      []string{"get", "messages", "topic23", "--header", "trace-id~=("},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid --header 'trace-id~=\('`),
+			},
+			expectedError: true,
+		},
+		{
+			name:                 "read committed transactions",
+			createTopics:         []string{"topic9"},
+			abortedTransaction:   []string{"Aborted 1"},
+			committedTransaction: []string{"Committed 1"},
+			getArgs:              []string{"get", "messages", "topic9", "--isolation", "read_committed"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Headers\s+topic9\s+0\s+2\s+<null>\s+Committed 1\s+$`),
+			},
+			expectedError: false,
+		},
+		{
+			name:                 "transactions with control records",
+			createTopics:         []string{"topic10"},
+			abortedTransaction:   []string{"Aborted 1"},
+			committedTransaction: []string{"Committed 1"},
+			getArgs:              []string{"get", "messages", "topic10", "--show-control-records"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`topic10\s+0\s+3\s+<COMMIT marker, producer id \d+>\s+topic10\s+0\s+2\s+<null>\s+Committed 1\s+topic10\s+0\s+1\s+<ABORT marker, producer id \d+>\s+topic10\s+0\s+0\s+<null>\s+Aborted 1`),
+			},
+			expectedError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -167,6 +406,16 @@ func TestGetMessages(t *testing.T) {
 				}
 			}
 
+			for _, record := range tt.produceRecords {
+				test_helpers.ProduceRecord(t, cl, record)
+			}
+			if len(tt.abortedTransaction) > 0 {
+				test_helpers.ProduceTransaction(t, brokers, tt.createTopics[0], tt.abortedTransaction, false)
+			}
+			if len(tt.committedTransaction) > 0 {
+				test_helpers.ProduceTransaction(t, brokers, tt.createTopics[0], tt.committedTransaction, true)
+			}
+
 			output, err := test_helpers.ExecuteCommandWrapper(tt.getArgs)
 
 			for _, pattern := range tt.expectedPatterns {
@@ -12,11 +12,35 @@ import (
 )
 
 var partitionsCmd = &cobra.Command{
-	Use:   "partitions <topic_name>",
-	Short: "Display partitions of a topic",
-	Long:  `Display partitions of a topic`,
-	Args:  cobra.ExactArgs(1),
+	Use:   "partitions <topic_name> | --under-replicated | --offline | --under-min-isr | --unavailable",
+	Short: "Display partitions of a topic, or the unhealthy partitions of all topics",
+	Long: `Display partitions of a topic, with their latest offset
+
+The unhealthy partitions of all the topics of the cluster, or of a topic, are displayed with their leader and replicas
+instead with:
+- --under-replicated: some replicas are not in sync with the leader
+- --offline: the partition has no leader, or its leader is not a live broker
+- --under-min-isr: fewer replicas are in sync than the min.insync.replicas of the topic, messages produced with
+  acks=all are rejected
+- --unavailable: the partition is offline or under its min ISR, messages produced with acks=all are rejected
+
+- kacao get partitions --under-replicated
+- kacao get partitions orders --unavailable`,
+	Args: func(command *cobra.Command, args []string) error {
+		for _, filter := range cmd.PartitionHealthFilters {
+			if command.Flags().Changed(filter) {
+				return cobra.MaximumNArgs(1)(command, args)
+			}
+		}
+		return cobra.ExactArgs(1)(command, args)
+	},
 	RunE: func(command *cobra.Command, args []string) error {
+		for _, filter := range cmd.PartitionHealthFilters {
+			if command.Flags().Changed(filter) {
+				return getUnhealthyPartitions(command, args, filter)
+			}
+		}
+
 		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
 		cobra.CheckErr(err)
 		consumerGroup, err := cmd.GetConsumerGroup()
@@ -71,6 +95,70 @@ var partitionsCmd = &cobra.Command{
 	},
 }
 
+// getUnhealthyPartitions displays the partitions of the topics, or of all topics, which match a health filter
+func getUnhealthyPartitions(command *cobra.Command, topics []string, filter string) error {
+	boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+	cobra.CheckErr(err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+	cobra.CheckErr(err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	ctx := context.Background()
+	metadata, err := adminClient.Metadata(ctx, topics...)
+	if err != nil {
+		return fmt.Errorf("error describing topics: %v", err)
+	}
+	for _, topic := range topics {
+		if topicDetail, ok := metadata.Topics[topic]; !ok || topicDetail.Err != nil {
+			return fmt.Errorf("topic '%s' does not exist in the cluster", topic)
+		}
+	}
+	liveBrokers := metadata.Brokers.NodeIDs()
+
+	var minISRs map[string]int
+	if filter == "under-min-isr" || filter == "unavailable" {
+		minISRs, err = cmd.DescribeMinISRs(ctx, adminClient, metadata.Topics.Names()...)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(command.OutOrStdout(), "%-30s%-12s%-10s%-25s%-25s%s\n", "Topic", "Partition", "Leader", "Replicas", "Synced replicas", "Offline replicas")
+	cobra.CheckErr(err)
+	for _, topicDetail := range metadata.Topics.Sorted() {
+		if topicDetail.Err != nil {
+			return fmt.Errorf("error describing topic '%s': %v", topicDetail.Topic, topicDetail.Err)
+		}
+		for _, partition := range topicDetail.Partitions.Sorted() {
+			var matches bool
+			switch filter {
+			case "under-replicated":
+				matches = cmd.IsUnderReplicated(partition)
+			case "offline":
+				matches = cmd.IsOffline(partition, liveBrokers)
+			case "under-min-isr":
+				matches = cmd.IsUnderMinISR(partition, minISRs[partition.Topic])
+			case "unavailable":
+				matches = cmd.IsUnavailable(partition, liveBrokers, minISRs[partition.Topic])
+			}
+			if !matches {
+				continue
+			}
+			_, err := fmt.Fprintf(command.OutOrStdout(), "%-30s%-12d%-10s%-25v%-25v%v\n", partition.Topic, partition.Partition,
+				cmd.FormatLeader(partition.Leader), partition.Replicas, partition.ISR, partition.OfflineReplicas)
+			cobra.CheckErr(err)
+		}
+	}
+	return nil
+}
+
 func init() {
+	partitionsCmd.Flags().Bool("under-replicated", false, "Display the partitions of all topics whose replicas are not all in sync")
+	partitionsCmd.Flags().Bool("offline", false, "Display the partitions of all topics without a live leader")
+	partitionsCmd.Flags().Bool("under-min-isr", false, "Display the partitions of all topics with fewer in-sync replicas than their min.insync.replicas")
+	partitionsCmd.Flags().Bool("unavailable", false, "Display the partitions of all topics which are offline or under their min.insync.replicas")
+	partitionsCmd.MarkFlagsMutuallyExclusive(cmd.PartitionHealthFilters...)
 	getCmd.AddCommand(partitionsCmd)
 }
@@ -143,3 +143,128 @@ func TestGetPartitions(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUnhealthyPartitions(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	// A single replica can not satisfy a min.insync.replicas of 2
+	minISR := "2"
+	_, err = adminClient.CreateTopic(ctx, 2, 1, map[string]*string{"min.insync.replicas": &minISR}, "payments")
+	assert.NoError(t, err)
+	_, err = adminClient.CreateTopic(ctx, 1, 1, nil, "orders")
+	assert.NoError(t, err)
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+	}{
+		{
+			name: "no under-replicated partition",
+			args: []string{"get", "partitions", "--under-replicated"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Topic\s+Partition\s+Leader\s+Replicas\s+Synced replicas\s+Offline replicas\n$`),
+			},
+		},
+		{
+			name: "no offline partition",
+			args: []string{"get", "partitions", "--offline"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Topic\s+Partition\s+Leader\s+Replicas\s+Synced replicas\s+Offline replicas\n$`),
+			},
+		},
+		{
+			name: "partitions under min ISR",
+			args: []string{"get", "partitions", "--under-min-isr"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Topic\s+Partition\s+Leader\s+Replicas\s+Synced replicas\s+Offline replicas\n` +
+					`payments\s+0\s+1\s+\[1\]\s+\[1\]\s+\[\]\n` +
+					`payments\s+1\s+1\s+\[1\]\s+\[1\]\s+\[\]\n$`),
+			},
+		},
+		{
+			name: "unavailable partitions of a topic",
+			args: []string{"get", "partitions", "payments", "--unavailable"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`\npayments\s+0\s+1\s+\[1\]\s+\[1\]\s+\[\]\npayments\s+1\s+1\s+\[1\]\s+\[1\]\s+\[\]\n$`),
+			},
+		},
+		{
+			name: "no unavailable partition of a healthy topic",
+			args: []string{"get", "partitions", "orders", "--unavailable"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Topic\s+Partition\s+Leader\s+Replicas\s+Synced replicas\s+Offline replicas\n$`),
+			},
+		},
+		{
+			name:          "unhealthy partitions of an unknown topic",
+			args:          []string{"get", "partitions", "unknown", "--offline"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: topic 'unknown' does not exist in the cluster`),
+			},
+		},
+		{
+			name:          "several filters",
+			args:          []string{"get", "partitions", "--offline", "--under-replicated"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: if any flags in the group \[under-replicated offline under-min-isr unavailable\] are set none of the others can be`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var quotasCmd = &cobra.Command{
+	Use:   "quotas [--user <user>] [--client-id <client_id>] [--ip <ip>] [--default-user] [--default-client-id] [--default-ip] [--strict]",
+	Short: "Display client quotas of the current cluster",
+	Long: `Display the client quotas of the current cluster, all of them or those of users, client ids and IPs.
+
+Quotas are set on entities made of a user, a client id or both, or of an IP, and on the defaults of these types:
+- kacao get quotas
+- kacao get quotas --user alice
+- kacao get quotas --default-user
+
+Without --strict, the quotas of entities which combine the filtered components with others are displayed too, such as
+the quotas of user alice with client id billing-service for --user alice.`,
+	Args: cobra.NoArgs,
+	RunE: func(command *cobra.Command, args []string) error {
+		entity, err := cmd.GetQuotaEntity(command)
+		if err != nil {
+			return err
+		}
+		strict, err := command.Flags().GetBool("strict")
+		cobra.CheckErr(err)
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		quotas, err := adminClient.DescribeClientQuotas(context.Background(), strict, cmd.QuotaEntityFilter(entity))
+		if err != nil {
+			return fmt.Errorf("error describing client quotas: %v", err)
+		}
+		return cmd.PrintQuotas(command.OutOrStdout(), quotas)
+	},
+}
+
+func init() {
+	cmd.AddQuotaEntityFlags(quotasCmd)
+	quotasCmd.Flags().Bool("strict", false, "Only display the quotas of entities made of exactly the filtered components")
+	getCmd.AddCommand(quotasCmd)
+}
@@ -0,0 +1,142 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestGetQuotas(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	alice := "alice"
+	billing := "billing-service"
+	responses, err := adminClient.AlterClientQuotas(ctx, []kadm.AlterClientQuotaEntry{
+		{
+			Entity: kadm.ClientQuotaEntity{{Type: "user", Name: &alice}},
+			Ops:    []kadm.AlterClientQuotaOp{{Key: "producer_byte_rate", Value: 1048576}},
+		},
+		{
+			Entity: kadm.ClientQuotaEntity{{Type: "user", Name: &alice}, {Type: "client-id", Name: &billing}},
+			Ops:    []kadm.AlterClientQuotaOp{{Key: "request_percentage", Value: 50}},
+		},
+		{
+			Entity: kadm.ClientQuotaEntity{{Type: "user"}},
+			Ops:    []kadm.AlterClientQuotaOp{{Key: "consumer_byte_rate", Value: 2097152}},
+		},
+	})
+	assert.NoError(t, err)
+	for _, response := range responses {
+		assert.NoError(t, response.Err)
+	}
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+	}{
+		{
+			name: "get all quotas",
+			args: []string{"get", "quotas"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Entity\s+Quota\s+Value\s+\n` +
+					`user=<default>\s+consumer_byte_rate\s+2097152\s+\n` +
+					`user=alice\s+producer_byte_rate\s+1048576\s+\n` +
+					`user=alice, client-id=billing-service\s+request_percentage\s+50\s+\n$`),
+			},
+		},
+		{
+			name: "get quotas of a user",
+			args: []string{"get", "quotas", "--user", "alice"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Entity\s+Quota\s+Value\s+\n` +
+					`user=alice\s+producer_byte_rate\s+1048576\s+\n` +
+					`user=alice, client-id=billing-service\s+request_percentage\s+50\s+\n$`),
+			},
+		},
+		{
+			name: "get quotas of exactly a user",
+			args: []string{"get", "quotas", "--user", "alice", "--strict"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Entity\s+Quota\s+Value\s+\nuser=alice\s+producer_byte_rate\s+1048576\s+\n$`),
+			},
+		},
+		{
+			name: "get default quotas of users",
+			args: []string{"get", "quotas", "--default-user"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Entity\s+Quota\s+Value\s+\nuser=<default>\s+consumer_byte_rate\s+2097152\s+\n$`),
+			},
+		},
+		{
+			name:          "user and default user",
+			args:          []string{"get", "quotas", "--user", "alice", "--default-user"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: if any flags in the group \[user default-user\] are set none of the others can be`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+		})
+	}
+}
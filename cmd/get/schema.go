@@ -0,0 +1,68 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema <schema_id>",
+	Short: "Display a schema of the schema registry of the current cluster",
+	Long: `Display a schema of the schema registry of the current cluster, with the subjects and versions using it
+
+The id of a schema is printed by 'kacao get subjects' and 'kacao describe subject <subject_name>', and is part of the
+messages serialized with it.`,
+	Args: func(command *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(command, args); err != nil {
+			return err
+		}
+		if _, err := strconv.Atoi(args[0]); err != nil {
+			return fmt.Errorf("<schema_id> must be a number")
+		}
+		return nil
+	},
+	RunE: func(command *cobra.Command, args []string) error {
+		id, err := strconv.Atoi(args[0])
+		cobra.CheckErr(err)
+
+		client, err := cmd.NewSchemaRegistryClient()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		schema, err := client.SchemaByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("error fetching schema %d: %v", id, err)
+		}
+		subjectVersions, err := client.SchemaVersionsByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("error fetching subjects of schema %d: %v", id, err)
+		}
+		usages := make([]string, 0, len(subjectVersions))
+		for _, subjectVersion := range subjectVersions {
+			usages = append(usages, fmt.Sprintf("%s:%d", subjectVersion.Subject, subjectVersion.Version))
+		}
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-30s%d\n", "Schema ID: ", id)
+		cobra.CheckErr(err)
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-30s%s\n", "Type: ", schema.Type)
+		cobra.CheckErr(err)
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-30s%s\n", "Subjects: ", strings.Join(usages, ","))
+		cobra.CheckErr(err)
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-30s%s\n", "References: ", cmd.FormatReferences(schema.References))
+		cobra.CheckErr(err)
+		_, err = fmt.Fprintf(command.OutOrStdout(), "\n%s\n", cmd.FormatSchema(schema))
+		cobra.CheckErr(err)
+
+		return nil
+	},
+}
+
+func init() {
+	getCmd.AddCommand(schemaCmd)
+}
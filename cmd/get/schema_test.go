@@ -0,0 +1,100 @@
+package get
+
+import (
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+	"regexp"
+	"testing"
+)
+
+func TestGetSchema(t *testing.T) {
+	registry := srfake.New()
+	defer registry.Close()
+	registry.SeedSchema("item-value", 1, 1, sr.Schema{Schema: `{"type":"record","name":"Item","fields":[{"name":"name","type":"string"}]}`})
+	registry.SeedSchema("orders-value", 1, 2, sr.Schema{
+		Schema:     `{"type":"record","name":"Order","fields":[{"name":"item","type":"Item"}]}`,
+		References: []sr.SchemaReference{{Name: "Item", Subject: "item-value", Version: 1}},
+	})
+	// The same schema registered under another subject keeps its id
+	client, err := sr.NewClient(sr.URLs(registry.URL()))
+	assert.NoError(t, err)
+	copied, err := client.CreateSchema(t.Context(), "orders-copy-value", sr.Schema{
+		Schema:     `{"type":"record","name":"Order","fields":[{"name":"item","type":"Item"}]}`,
+		References: []sr.SchemaReference{{Name: "Item", Subject: "item-value", Version: 1}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, copied.ID)
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": []string{"localhost:9092"},
+				"schema-registry":   registry.URL(),
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+	tempDir := test_helpers.SetupTest(t, testConfig)
+	defer test_helpers.CleanupTestConfig(t, tempDir)
+
+	tests := []struct {
+		name             string
+		getArgs          []string
+		expectedPatterns []*regexp.Regexp
+		expectedError    bool
+	}{
+		{
+			name:    "get schema",
+			getArgs: []string{"get", "schema", "1"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Schema ID:\s+1\nType:\s+AVRO\nSubjects:\s+item-value:1\nReferences:\s+-\n`),
+				regexp.MustCompile(`\{\n  "type": "record",\n  "name": "Item",`),
+			},
+		},
+		{
+			name:    "get schema with references used by several subjects",
+			getArgs: []string{"get", "schema", "2"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Subjects:\s+(orders-value:1,orders-copy-value:1|orders-copy-value:1,orders-value:1)\n`),
+				regexp.MustCompile(`References:\s+Item=item-value:1\n`),
+			},
+		},
+		{
+			name:          "get unknown schema",
+			getArgs:       []string{"get", "schema", "42"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: error fetching schema 42`),
+			},
+		},
+		{
+			name:          "get schema with invalid id",
+			getArgs:       []string{"get", "schema", "orders"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: <schema_id> must be a number`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := test_helpers.ExecuteCommandWrapper(tt.getArgs)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+		})
+	}
+}
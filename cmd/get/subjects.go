@@ -0,0 +1,64 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/Vidalee/kacao/serde"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/sr"
+	"slices"
+)
+
+var subjectsCmd = &cobra.Command{
+	Use:   "subjects",
+	Short: "Display subjects of the schema registry of the current cluster",
+	Long: `Display subjects of the schema registry of the current cluster, with their latest schema
+
+You can also specify subject names to only display these subjects:
+- kacao get subjects <subject_name>
+- kacao get subjects <subject_name_1> <subject_name_2> ...
+
+Soft deleted subjects are displayed with --deleted.`,
+	RunE: func(command *cobra.Command, args []string) error {
+		client, err := cmd.NewSchemaRegistryClient()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		deleted, err := command.Flags().GetBool("deleted")
+		cobra.CheckErr(err)
+		if deleted {
+			ctx = sr.WithParams(ctx, sr.ShowDeleted)
+		}
+
+		subjects, err := client.Subjects(ctx)
+		if err != nil {
+			return fmt.Errorf("error listing subjects: %v", err)
+		}
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-50s%-20s%-20s%-20s\n", "Subject", "Latest version", "Schema ID", "Type")
+		cobra.CheckErr(err)
+
+		for _, subject := range subjects {
+			if len(args) > 0 && !slices.Contains(args, subject) {
+				continue
+			}
+
+			latest, err := client.SchemaByVersion(ctx, subject, serde.LatestVersion)
+			if err != nil {
+				return fmt.Errorf("error fetching latest schema of subject '%s': %v", subject, err)
+			}
+			_, err = fmt.Fprintf(command.OutOrStdout(), "%-50s%-20d%-20d%-20s\n", subject, latest.Version, latest.ID, latest.Type)
+			cobra.CheckErr(err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	subjectsCmd.Flags().Bool("deleted", false, "Also show soft deleted subjects")
+	getCmd.AddCommand(subjectsCmd)
+}
@@ -0,0 +1,119 @@
+package get
+
+import (
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+	"regexp"
+	"testing"
+)
+
+func TestGetSubjects(t *testing.T) {
+	registry := srfake.New()
+	defer registry.Close()
+	registry.SeedSchema("orders-value", 1, 1, sr.Schema{Schema: `{"type": "string"}`})
+	registry.SeedSchema("orders-value", 2, 3, sr.Schema{Schema: `{"type": "long"}`})
+	registry.SeedSchema("users-value", 1, 2, sr.Schema{Schema: `syntax = "proto3"; message User {}`, Type: sr.TypeProtobuf})
+	registry.SeedSchema("deleted-value", 1, 4, sr.Schema{Schema: `{"type": "object"}`, Type: sr.TypeJSON})
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": []string{"localhost:9092"},
+				"schema-registry":   registry.URL(),
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tempDir := test_helpers.SetupTest(t, testConfig)
+	defer test_helpers.CleanupTestConfig(t, tempDir)
+	client, err := sr.NewClient(sr.URLs(registry.URL()))
+	assert.NoError(t, err)
+	_, err = client.DeleteSubject(t.Context(), "deleted-value", sr.SoftDelete)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		getArgs            []string
+		expectedPatterns   []*regexp.Regexp
+		unexpectedPatterns []*regexp.Regexp
+		expectedError      bool
+	}{
+		{
+			name:    "get all subjects",
+			getArgs: []string{"get", "subjects"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Subject\s+Latest version\s+Schema ID\s+Type`),
+				regexp.MustCompile(`orders-value\s+2\s+3\s+AVRO`),
+				regexp.MustCompile(`users-value\s+1\s+2\s+PROTOBUF`),
+			},
+			unexpectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`deleted-value`),
+			},
+		},
+		{
+			name:    "get a subject",
+			getArgs: []string{"get", "subjects", "users-value"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`users-value\s+1\s+2\s+PROTOBUF`),
+			},
+			unexpectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`orders-value`),
+			},
+		},
+		{
+			name:    "get deleted subjects",
+			getArgs: []string{"get", "subjects", "--deleted"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`deleted-value\s+1\s+4\s+JSON`),
+				regexp.MustCompile(`orders-value\s+2\s+3\s+AVRO`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := test_helpers.ExecuteCommandWrapper(tt.getArgs)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+			for _, pattern := range tt.unexpectedPatterns {
+				assert.NotRegexp(t, pattern, output)
+			}
+		})
+	}
+}
+
+func TestGetSubjectsWithoutSchemaRegistry(t *testing.T) {
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": []string{"localhost:9092"},
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+	tempDir := test_helpers.SetupTest(t, testConfig)
+	defer test_helpers.CleanupTestConfig(t, tempDir)
+
+	output, err := test_helpers.ExecuteCommandWrapper([]string{"get", "subjects"})
+	assert.Error(t, err)
+	assert.Contains(t, output, "Error: no schema registry set for cluster 'test-cluster'")
+}
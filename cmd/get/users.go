@@ -0,0 +1,55 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var usersCmd = &cobra.Command{
+	Use:   "users [user_name ...]",
+	Short: "Display SCRAM users of the current cluster",
+	Long: `Display the users with SCRAM credentials of the current cluster, with their mechanisms and iterations.
+
+All users are displayed by default, or only the specified ones:
+- kacao get users
+- kacao get users alice billing-service
+
+Passwords cannot be read back from the cluster, which only stores their salted hash.`,
+	RunE: func(command *cobra.Command, args []string) error {
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		users, err := adminClient.DescribeUserSCRAMs(context.Background(), args...)
+		if err != nil {
+			return fmt.Errorf("error describing users: %v", err)
+		}
+		for _, user := range users.Sorted() {
+			if user.Err != nil {
+				return fmt.Errorf("error describing user '%s': %v", user.User, user.Err)
+			}
+		}
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-40s%-20s%-10s\n", "User", "Mechanism", "Iterations")
+		cobra.CheckErr(err)
+		for _, user := range users.Sorted() {
+			for _, credInfo := range user.CredInfos {
+				_, err := fmt.Fprintf(command.OutOrStdout(), "%-40s%-20s%-10d\n", user.User, credInfo.Mechanism, credInfo.Iterations)
+				cobra.CheckErr(err)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	getCmd.AddCommand(usersCmd)
+}
@@ -0,0 +1,113 @@
+package get
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestGetUsers(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	results, err := adminClient.AlterUserSCRAMs(ctx, nil, []kadm.UpsertSCRAM{
+		{User: "alice", Mechanism: kadm.ScramSha512, Iterations: 8192, Password: "alice-secret"},
+		{User: "alice", Mechanism: kadm.ScramSha256, Iterations: 4096, Password: "alice-secret"},
+		{User: "billing-service", Mechanism: kadm.ScramSha512, Iterations: 16384, Password: "billing-secret"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, results.Error())
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+	}{
+		{
+			name: "get all users",
+			args: []string{"get", "users"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^User\s+Mechanism\s+Iterations\s+\n`),
+				regexp.MustCompile(`\nalice\s+SCRAM-SHA-256\s+4096\s+\n`),
+				regexp.MustCompile(`\nalice\s+SCRAM-SHA-512\s+8192\s+\n`),
+				regexp.MustCompile(`\nbilling-service\s+SCRAM-SHA-512\s+16384\s+\n$`),
+			},
+		},
+		{
+			name: "get a user",
+			args: []string{"get", "users", "billing-service"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^User\s+Mechanism\s+Iterations\s+\nbilling-service\s+SCRAM-SHA-512\s+16384\s+\n$`),
+			},
+		},
+		{
+			name:          "get an unknown user",
+			args:          []string{"get", "users", "carol"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: error describing user 'carol': RESOURCE_NOT_FOUND`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	IsolationReadUncommitted = "read_uncommitted"
+	IsolationReadCommitted   = "read_committed"
+)
+
+// Isolation is how transactional records are read, from the --isolation and --show-control-records flags
+type Isolation struct {
+	ReadCommitted bool
+	// ShowControlRecords displays the commit and abort markers written at the end of transactions
+	ShowControlRecords bool
+}
+
+func AddIsolationFlags(command *cobra.Command) {
+	command.Flags().String("isolation", IsolationReadUncommitted, "Isolation level: read_uncommitted shows every message, including those of aborted transactions, read_committed hides the messages of aborted and ongoing transactions")
+	command.Flags().Bool("show-control-records", false, "Show the commit and abort markers of transactions")
+}
+
+func GetIsolationFlags(command *cobra.Command) (Isolation, error) {
+	isolationArg, err := command.Flags().GetString("isolation")
+	if err != nil {
+		return Isolation{}, err
+	}
+	showControlRecords, err := command.Flags().GetBool("show-control-records")
+	if err != nil {
+		return Isolation{}, err
+	}
+	switch isolationArg {
+	case IsolationReadUncommitted, IsolationReadCommitted:
+	default:
+		return Isolation{}, fmt.Errorf("invalid --isolation '%s'. Use one of: %s, %s", isolationArg, IsolationReadUncommitted, IsolationReadCommitted)
+	}
+	return Isolation{ReadCommitted: isolationArg == IsolationReadCommitted, ShowControlRecords: showControlRecords}, nil
+}
+
+// ClientOpts returns the options of a consuming client for this isolation
+func (i Isolation) ClientOpts() []kgo.Opt {
+	isolationLevel := kgo.ReadUncommitted()
+	if i.ReadCommitted {
+		isolationLevel = kgo.ReadCommitted()
+	}
+	opts := []kgo.Opt{kgo.FetchIsolationLevel(isolationLevel)}
+	if i.ShowControlRecords {
+		opts = append(opts, kgo.KeepControlRecords())
+	}
+	return opts
+}
+
+// ListEndOffsets lists the offsets a consumer with this isolation can read up to: the high watermark, or the last
+// stable offset for read_committed, which stops at the first ongoing transaction
+func (i Isolation) ListEndOffsets(ctx context.Context, adminClient *kadm.Client, topics ...string) (kadm.ListedOffsets, error) {
+	if i.ReadCommitted {
+		return adminClient.ListCommittedOffsets(ctx, topics...)
+	}
+	return adminClient.ListEndOffsets(ctx, topics...)
+}
+
+// ControlRecordType returns the type of a control record, ABORT or COMMIT for transaction markers.
+// Their key is made of a version and a type, both int16. Under read_uncommitted, the markers are the only way to tell
+// which records were aborted, as fetch responses only list aborted transactions to read_committed consumers.
+func ControlRecordType(record *kgo.Record) string {
+	if len(record.Key) < 4 {
+		return "UNKNOWN"
+	}
+	switch int16(binary.BigEndian.Uint16(record.Key[2:4])) {
+	case 0:
+		return "ABORT"
+	case 1:
+		return "COMMIT"
+	}
+	return "UNKNOWN"
+}
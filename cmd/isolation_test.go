@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestControlRecordType(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      []byte
+		expected string
+	}{
+		{"abort marker", []byte{0, 0, 0, 0}, "ABORT"},
+		{"commit marker", []byte{0, 0, 0, 1}, "COMMIT"},
+		{"invalid key", []byte{0}, "UNKNOWN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ControlRecordType(&kgo.Record{Key: tt.key}))
+		})
+	}
+}
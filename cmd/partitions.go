@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+// PartitionHealthFilters are the flags of 'kacao get partitions' which display the unhealthy partitions of all topics
+var PartitionHealthFilters = []string{"under-replicated", "offline", "under-min-isr", "unavailable"}
+
+// IsUnderReplicated tells whether some replicas of a partition are not in sync
+func IsUnderReplicated(partition kadm.PartitionDetail) bool {
+	return len(partition.ISR) < len(partition.Replicas)
+}
+
+// IsOffline tells whether a partition has no leader, or a leader which is not a live broker
+func IsOffline(partition kadm.PartitionDetail, liveBrokers []int32) bool {
+	return partition.Leader < 0 || !slices.Contains(liveBrokers, partition.Leader)
+}
+
+// IsUnderMinISR tells whether a partition has fewer in-sync replicas than the min.insync.replicas of its topic, which
+// rejects the messages produced with acks=all
+func IsUnderMinISR(partition kadm.PartitionDetail, minISR int) bool {
+	return len(partition.ISR) < minISR
+}
+
+// IsUnavailable tells whether a partition can not be produced to with acks=all, being offline or under its min ISR
+func IsUnavailable(partition kadm.PartitionDetail, liveBrokers []int32, minISR int) bool {
+	return IsOffline(partition, liveBrokers) || IsUnderMinISR(partition, minISR)
+}
+
+// IsLeaderNotPreferred tells whether a partition is led by another replica than its preferred replica, its first one
+func IsLeaderNotPreferred(partition kadm.PartitionDetail) bool {
+	return len(partition.Replicas) > 0 && partition.Leader != partition.Replicas[0]
+}
+
+// DescribeMinISRs returns the min.insync.replicas config of the topics, 1 when it is not set
+func DescribeMinISRs(ctx context.Context, adminClient *kadm.Client, topics ...string) (map[string]int, error) {
+	minISRs := make(map[string]int)
+	if len(topics) == 0 {
+		return minISRs, nil
+	}
+	resourceConfigs, err := adminClient.DescribeTopicConfigs(ctx, topics...)
+	if err != nil {
+		return nil, fmt.Errorf("error describing configs of topics: %v", err)
+	}
+	for _, resourceConfig := range resourceConfigs {
+		if resourceConfig.Err != nil {
+			return nil, fmt.Errorf("error describing configs of topic '%s': %v", resourceConfig.Name, resourceConfig.Err)
+		}
+		minISRs[resourceConfig.Name] = 1
+		for _, config := range resourceConfig.Configs {
+			if config.Key != "min.insync.replicas" || config.Value == nil {
+				continue
+			}
+			minISR, err := strconv.Atoi(*config.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid min.insync.replicas '%s' of topic '%s'", *config.Value, resourceConfig.Name)
+			}
+			minISRs[resourceConfig.Name] = minISR
+		}
+	}
+	return minISRs, nil
+}
+
+// FormatLeader renders the leader of a partition, or none when it has no leader
+func FormatLeader(leader int32) string {
+	if leader < 0 {
+		return "none"
+	}
+	return strconv.Itoa(int(leader))
+}
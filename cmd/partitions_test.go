@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func TestPartitionHealth(t *testing.T) {
+	liveBrokers := []int32{1, 2, 3}
+	tests := []struct {
+		name                    string
+		partition               kadm.PartitionDetail
+		minISR                  int
+		expectedUnderReplicated bool
+		expectedOffline         bool
+		expectedUnderMinISR     bool
+		expectedUnavailable     bool
+		expectedNotPreferred    bool
+	}{
+		{
+			name:      "healthy",
+			partition: kadm.PartitionDetail{Leader: 1, Replicas: []int32{1, 2, 3}, ISR: []int32{1, 2, 3}},
+			minISR:    2,
+		},
+		{
+			name:                    "under-replicated",
+			partition:               kadm.PartitionDetail{Leader: 1, Replicas: []int32{1, 2, 3}, ISR: []int32{1, 2}, OfflineReplicas: []int32{3}},
+			minISR:                  2,
+			expectedUnderReplicated: true,
+		},
+		{
+			name:                    "under min ISR",
+			partition:               kadm.PartitionDetail{Leader: 2, Replicas: []int32{1, 2, 3}, ISR: []int32{2}},
+			minISR:                  2,
+			expectedUnderReplicated: true,
+			expectedUnderMinISR:     true,
+			expectedUnavailable:     true,
+			expectedNotPreferred:    true,
+		},
+		{
+			name:                 "no leader",
+			partition:            kadm.PartitionDetail{Leader: -1, Replicas: []int32{1, 2}, ISR: []int32{1, 2}},
+			minISR:               1,
+			expectedOffline:      true,
+			expectedUnavailable:  true,
+			expectedNotPreferred: true,
+		},
+		{
+			name:                "leader on a dead broker",
+			partition:           kadm.PartitionDetail{Leader: 4, Replicas: []int32{4}, ISR: []int32{4}},
+			minISR:              1,
+			expectedOffline:     true,
+			expectedUnavailable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedUnderReplicated, IsUnderReplicated(tt.partition))
+			assert.Equal(t, tt.expectedOffline, IsOffline(tt.partition, liveBrokers))
+			assert.Equal(t, tt.expectedUnderMinISR, IsUnderMinISR(tt.partition, tt.minISR))
+			assert.Equal(t, tt.expectedUnavailable, IsUnavailable(tt.partition, liveBrokers, tt.minISR))
+			assert.Equal(t, tt.expectedNotPreferred, IsLeaderNotPreferred(tt.partition))
+		})
+	}
+}
+
+func TestFormatLeader(t *testing.T) {
+	assert.Equal(t, "none", FormatLeader(-1))
+	assert.Equal(t, "3", FormatLeader(3))
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	minPollBackoff = 250 * time.Millisecond
+	maxPollBackoff = 10 * time.Second
+)
+
+// PollRecords polls records with cl and passes them to handle, until handle returns false or ctx is done.
+// Retriable fetch errors are reported to errOut and retried with an exponential backoff.
+// It returns the error of ctx once it is done, and the first fetch error that cannot be retried.
+func PollRecords(ctx context.Context, cl *kgo.Client, errOut io.Writer, handle func(record *kgo.Record) bool) error {
+	backoff := minPollBackoff
+	for {
+		fetches := cl.PollFetches(ctx)
+		// The records of the last poll are handled before returning, even if ctx is done meanwhile
+		iter := fetches.RecordIter()
+		for !iter.Done() {
+			if !handle(iter.Next()) {
+				return nil
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var retriableErr error
+		for _, fetchErr := range fetches.Errors() {
+			if errors.Is(fetchErr.Err, context.Canceled) || errors.Is(fetchErr.Err, context.DeadlineExceeded) {
+				return ctx.Err()
+			}
+			if !isRetriable(fetchErr.Err) {
+				return fmt.Errorf("error fetching records from topic '%s' partition %d: %v", fetchErr.Topic, fetchErr.Partition, fetchErr.Err)
+			}
+			retriableErr = fetchErr.Err
+		}
+		if retriableErr == nil {
+			backoff = minPollBackoff
+			continue
+		}
+
+		_, err := fmt.Fprintf(errOut, "Error fetching records, retrying in %s: %v\n", backoff, retriableErr)
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxPollBackoff)
+	}
+}
+
+func isRetriable(err error) bool {
+	var netErr net.Error
+	return kerr.IsRetriable(err) || errors.As(err, &netErr) || errors.Is(err, io.EOF)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+func TestIsRetriable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"retriable kafka error", kerr.NotLeaderForPartition, true},
+		{"non retriable kafka error", kerr.TopicAuthorizationFailed, false},
+		{"connection closed", fmt.Errorf("reading response: %w", io.EOF), true},
+		{"context canceled", context.Canceled, false},
+		{"other error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isRetriable(tt.err))
+		})
+	}
+}
+
+func TestExitCodeError(t *testing.T) {
+	err := fmt.Errorf("consume: %w", ErrInterrupted)
+	var exitCodeError *ExitCodeError
+	assert.True(t, errors.As(err, &exitCodeError))
+	assert.Equal(t, 130, exitCodeError.Code)
+	assert.EqualError(t, err, "consume: interrupted")
+}
@@ -6,15 +6,37 @@ import (
 	"github.com/Vidalee/kacao/cmd"
 	"github.com/spf13/cobra"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"io"
+	"os"
 	"strings"
 	"sync"
 )
 
 var produceCmd = &cobra.Command{
-	Use:   "produce <topic> --message <message> [--key <key>] [--header <key=value>]",
+	Use:   "produce <topic> (--message <message> | --tombstone | --from-file <file>) [--key <key> | --null-key] [--header <key=value>]",
 	Short: "Produce messages to a topic",
-	Long:  `Produce messages to a topic`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Produce messages to a topic
+
+By default the key of the message is empty. Use --null-key to send a null key instead.
+Use --tombstone instead of --message to send a null value, which deletes the key from a compacted topic:
+- kacao produce <topic> --key my-key --tombstone
+
+Use --from-file to produce messages read from a file (or stdin with -), one JSON message per line.
+This is the format printed by 'kacao consume --format jsonl', so messages can be copied from a topic to another:
+- kacao consume <source_topic> --format jsonl > messages.jsonl
+- kacao produce <destination_topic> --from-file messages.jsonl
+Only the key, value, headers and timestamp of each message are used. The jsonl format keeps the raw bytes of the keys
+and values, so messages in the wire format of the schema registry are copied as is.
+
+Use --value-schema-subject to encode JSON values with a schema of the schema registry of the cluster, in Avro, Protobuf
+or JSON Schema, and prefix them with the schema id. The latest version of the schema is used, unless
+--value-schema-version is set. Use --value-schema-file instead to encode with a local schema, which must already be
+registered under --value-schema-subject or <topic>-value, with 'kacao create schema' or by adding --value-register-schema.
+Keys work the same with the --key-schema-* flags:
+- kacao produce orders --value-schema-subject orders-value --message '{"id": 42, "item": "book"}'
+- kacao produce orders --key-schema-file order-key.avsc --key-register-schema --key '{"id": 42}' --message '...'
+Null keys and tombstones are not encoded. The schema flags also encode the JSON values of --from-file.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(command *cobra.Command, args []string) error {
 		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
 		cobra.CheckErr(err)
@@ -32,12 +54,21 @@ var produceCmd = &cobra.Command{
 		message, err := command.Flags().GetString("message")
 		cobra.CheckErr(err)
 
+		tombstone, err := command.Flags().GetBool("tombstone")
+		cobra.CheckErr(err)
+
 		key, err := command.Flags().GetString("key")
 		cobra.CheckErr(err)
 
+		nullKey, err := command.Flags().GetBool("null-key")
+		cobra.CheckErr(err)
+
 		headers, err := command.Flags().GetStringArray("header")
 		cobra.CheckErr(err)
 
+		fromFile, err := command.Flags().GetString("from-file")
+		cobra.CheckErr(err)
+
 		var kafkaHeaders []kgo.RecordHeader
 
 		for _, header := range headers {
@@ -47,26 +78,87 @@ var produceCmd = &cobra.Command{
 			}
 			kafkaHeaders = append(kafkaHeaders, kgo.RecordHeader{Key: parts[0], Value: []byte(parts[1])})
 		}
+		var records []*kgo.Record
+		if fromFile != "" {
+			records, err = readRecordsFromFile(command, args[0], fromFile)
+			if err != nil {
+				return err
+			}
+		} else {
+			record := &kgo.Record{Topic: args[0], Value: []byte(message), Key: []byte(key), Headers: kafkaHeaders}
+			if nullKey {
+				record.Key = nil
+			}
+			if tombstone {
+				record.Value = nil
+			}
+			records = append(records, record)
+		}
+		if err := encodeRecords(command, args[0], records); err != nil {
+			return err
+		}
+
 		var wg sync.WaitGroup
-		wg.Add(1)
-		record := &kgo.Record{Topic: args[0], Value: []byte(message), Key: []byte(key), Headers: kafkaHeaders}
-		cl.Produce(ctx, record, func(_ *kgo.Record, err error) {
-			defer wg.Done()
-			cobra.CheckErr(err)
-		})
+		wg.Add(len(records))
+		for _, record := range records {
+			cl.Produce(ctx, record, func(_ *kgo.Record, err error) {
+				defer wg.Done()
+				cobra.CheckErr(err)
+			})
+		}
 		wg.Wait()
 
 		return nil
 	},
 }
 
+func readRecordsFromFile(command *cobra.Command, topic string, path string) ([]*kgo.Record, error) {
+	var reader io.Reader
+	if path == "-" {
+		reader = command.InOrStdin()
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening file '%s': %v", path, err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	views, err := cmd.ReadRecordViews(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading messages from '%s': %v", path, err)
+	}
+	records := make([]*kgo.Record, 0, len(views))
+	for i, view := range views {
+		// Transaction markers are written by the brokers, they cannot be produced
+		if view.Control != "" {
+			continue
+		}
+		record, err := view.ToRecord(topic)
+		if err != nil {
+			return nil, fmt.Errorf("error reading message %d from '%s': %v", i+1, path, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
 func init() {
 	produceCmd.Flags().StringArrayP("header", "H", []string{}, "Header to add to the message, example: --header key=value")
 	produceCmd.Flags().StringP("key", "k", "", "Key of the message")
+	produceCmd.Flags().Bool("null-key", false, "Send a null key instead of an empty one")
 	produceCmd.Flags().StringP("message", "m", "", "Message to produce")
+	produceCmd.Flags().Bool("tombstone", false, "Send a null value (tombstone) instead of a message")
+	produceCmd.Flags().String("from-file", "", "Produce the messages of a file, one JSON message per line as printed by 'kacao consume --format jsonl'. Use - to read from stdin")
+
+	addSchemaFlags(produceCmd, "key")
+	addSchemaFlags(produceCmd, "value")
 
-	err := produceCmd.MarkFlagRequired("message")
-	cobra.CheckErr(err)
+	produceCmd.MarkFlagsOneRequired("message", "tombstone", "from-file")
+	produceCmd.MarkFlagsMutuallyExclusive("message", "tombstone", "from-file")
+	produceCmd.MarkFlagsMutuallyExclusive("key", "null-key", "from-file")
+	produceCmd.MarkFlagsMutuallyExclusive("header", "from-file")
 
 	cmd.RootCmd.AddCommand(produceCmd)
 }
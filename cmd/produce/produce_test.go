@@ -9,6 +9,10 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/kafka"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -33,10 +37,18 @@ func TestProduceMessages(t *testing.T) {
 	brokers, err := kafkaContainer.Brokers(ctx)
 	assert.NoError(t, err)
 
+	const greetingSchema = `{"type": "record", "name": "Greeting", "fields": [{"name": "text", "type": "string"}]}`
+	registry := srfake.New()
+	defer registry.Close()
+	registry.SeedSchema("greetings-value", 1, 1, sr.Schema{Schema: greetingSchema})
+	keySchemaFile := filepath.Join(t.TempDir(), "key.avsc")
+	assert.NoError(t, os.WriteFile(keySchemaFile, []byte(`{"type": "string"}`), 0644))
+
 	testConfig := test_helpers.TestConfig{
 		Clusters: map[string]map[string]interface{}{
 			"test-cluster": {
 				"bootstrap-servers": brokers,
+				"schema-registry":   registry.URL(),
 			},
 		},
 		Contexts: map[string]map[string]interface{}{
@@ -50,9 +62,12 @@ func TestProduceMessages(t *testing.T) {
 	tests := []struct {
 		name             string
 		produceArgs      []string
+		fromFileContent  string
 		expectedKey      string
 		expectedHeaders  map[string]string
 		expectedMessage  string
+		expectedNullKey  bool
+		expectedNullMsg  bool
 		expectedError    bool
 		expectedErrorMsg string
 	}{
@@ -91,6 +106,104 @@ func TestProduceMessages(t *testing.T) {
 			expectedError:    true,
 			expectedErrorMsg: "invalid header format. Expected key=value.",
 		},
+		{
+			name:            "produce message with null key",
+			produceArgs:     []string{"produce", "produce-topic-null-key", "--message", "Null Key Message", "--null-key"},
+			expectedMessage: "Null Key Message",
+			expectedNullKey: true,
+			expectedError:   false,
+		},
+		{
+			name:            "produce tombstone",
+			produceArgs:     []string{"produce", "produce-topic-tombstone", "--key", "my-key", "--tombstone"},
+			expectedKey:     "my-key",
+			expectedNullMsg: true,
+			expectedError:   false,
+		},
+		{
+			name:             "produce without message nor tombstone",
+			produceArgs:      []string{"produce", "produce-topic-no-message", "--key", "my-key"},
+			expectedError:    true,
+			expectedErrorMsg: "at least one of the flags in the group [message tombstone from-file] is required",
+		},
+		{
+			name:             "produce message and tombstone",
+			produceArgs:      []string{"produce", "produce-topic-message-tombstone", "--message", "Message", "--tombstone"},
+			expectedError:    true,
+			expectedErrorMsg: "if any flags in the group [message tombstone from-file] are set none of the others can be",
+		},
+		{
+			name:             "produce with key and null key",
+			produceArgs:      []string{"produce", "produce-topic-key-null-key", "--message", "Message", "--key", "my-key", "--null-key"},
+			expectedError:    true,
+			expectedErrorMsg: "if any flags in the group [key null-key from-file] are set none of the others can be",
+		},
+		{
+			name:            "produce messages from file",
+			produceArgs:     []string{"produce", "produce-topic-from-file"},
+			fromFileContent: `{"topic":"other-topic","partition":3,"offset":12,"key":"file-key","value":"File Message","headers":[{"key":"header-key","value":"header-value"}]}` + "\n",
+			expectedKey:     "file-key",
+			expectedHeaders: map[string]string{"header-key": "header-value"},
+			expectedMessage: "File Message",
+			expectedError:   false,
+		},
+		{
+			name:            "produce tombstone from file",
+			produceArgs:     []string{"produce", "produce-topic-from-file-tombstone"},
+			fromFileContent: `{"key":null,"value":null}` + "\n",
+			expectedNullKey: true,
+			expectedNullMsg: true,
+			expectedError:   false,
+		},
+		{
+			name:             "produce from file with invalid line",
+			produceArgs:      []string{"produce", "produce-topic-from-file-invalid"},
+			fromFileContent:  `{"key":"file-key","value":"File Message"}` + "\n" + `not json` + "\n",
+			expectedError:    true,
+			expectedErrorMsg: "invalid record on line 2",
+		},
+		{
+			name:            "produce value encoded with the latest schema of a subject",
+			produceArgs:     []string{"produce", "produce-topic-value-schema", "--value-schema-subject", "greetings-value", "--message", `{"text": "hello"}`},
+			expectedMessage: string(test_helpers.AvroWireFormat(t, 1, greetingSchema, map[string]any{"text": "hello"})),
+		},
+		{
+			name:             "produce key with a schema file which is not registered",
+			produceArgs:      []string{"produce", "produce-topic-key-schema-unregistered", "--key-schema-file", keySchemaFile, "--key", `"my-key"`, "--message", "Message"},
+			expectedError:    true,
+			expectedErrorMsg: "schema is not registered under subject 'produce-topic-key-schema-unregistered-key': " + keySchemaFile + ". Register it with 'kacao create schema produce-topic-key-schema-unregistered-key --file " + keySchemaFile + "', or use --key-register-schema",
+		},
+		{
+			name:            "produce key encoded with a schema file",
+			produceArgs:     []string{"produce", "produce-topic-key-schema", "--key-schema-file", keySchemaFile, "--key-register-schema", "--key", `"my-key"`, "--message", "Message"},
+			expectedKey:     string(test_helpers.AvroWireFormat(t, 2, `{"type": "string"}`, "my-key")),
+			expectedMessage: "Message",
+		},
+		{
+			name:            "produce messages from file encoded with a schema",
+			produceArgs:     []string{"produce", "produce-topic-from-file-schema", "--value-schema-subject", "greetings-value", "--value-schema-version", "1"},
+			fromFileContent: `{"key":"file-key","value":"{\"text\":\"hello\"}"}` + "\n",
+			expectedKey:     "file-key",
+			expectedMessage: string(test_helpers.AvroWireFormat(t, 1, greetingSchema, map[string]any{"text": "hello"})),
+		},
+		{
+			name:             "produce value not matching the schema",
+			produceArgs:      []string{"produce", "produce-topic-value-schema-invalid", "--value-schema-subject", "greetings-value", "--message", `{"name": "hello"}`},
+			expectedError:    true,
+			expectedErrorMsg: "error encoding value of message 1: error encoding data with schema 1: missing field 'text' of record Greeting",
+		},
+		{
+			name:             "produce with a schema version without subject",
+			produceArgs:      []string{"produce", "produce-topic-schema-version", "--value-schema-version", "1", "--message", "Message"},
+			expectedError:    true,
+			expectedErrorMsg: "--value-schema-subject or --value-schema-file is required to encode the value with a schema",
+		},
+		{
+			name:             "produce registering a schema without schema file",
+			produceArgs:      []string{"produce", "produce-topic-register-schema", "--value-schema-subject", "greetings-value", "--value-register-schema", "--message", "Message"},
+			expectedError:    true,
+			expectedErrorMsg: "--value-schema-file is required to register the schema of the value",
+		},
 	}
 
 	for _, tt := range tests {
@@ -112,7 +225,15 @@ func TestProduceMessages(t *testing.T) {
 			_, err = adminClient.CreateTopics(ctx, 1, 1, nil, topicName)
 			assert.NoError(t, err)
 
-			output, err := test_helpers.ExecuteCommandWrapper(tt.produceArgs)
+			produceArgs := tt.produceArgs
+			if tt.fromFileContent != "" {
+				filePath := filepath.Join(tempDir, "messages.jsonl")
+				err := os.WriteFile(filePath, []byte(tt.fromFileContent), 0644)
+				assert.NoError(t, err)
+				produceArgs = append(produceArgs, "--from-file", filePath)
+			}
+
+			output, err := test_helpers.ExecuteCommandWrapper(produceArgs)
 			if tt.expectedError {
 				assert.Error(t, err)
 				assert.Contains(t, output, tt.expectedErrorMsg)
@@ -146,6 +267,8 @@ func TestProduceMessages(t *testing.T) {
 
 			assert.Equal(t, tt.expectedMessage, string(records[0].Value))
 			assert.Equal(t, tt.expectedKey, string(records[0].Key))
+			assert.Equal(t, tt.expectedNullKey, records[0].Key == nil)
+			assert.Equal(t, tt.expectedNullMsg, records[0].Value == nil)
 
 			if len(tt.expectedHeaders) > 0 {
 				for _, header := range records[0].Headers {
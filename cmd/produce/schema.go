@@ -0,0 +1,113 @@
+package produce
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/Vidalee/kacao/serde"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+func addSchemaFlags(command *cobra.Command, field string) {
+	command.Flags().String(field+"-schema-subject", "", fmt.Sprintf("Encode the JSON %s with the schema of this subject of the schema registry", field))
+	command.Flags().Int(field+"-schema-version", serde.LatestVersion, fmt.Sprintf("Version of the schema of the %s, the latest by default", field))
+	command.Flags().String(field+"-schema-file", "", fmt.Sprintf("Encode the JSON %s with the schema of this file (.avsc, .proto or .json), which must be registered under --%s-schema-subject or <topic>-%s", field, field, field))
+	command.Flags().Bool(field+"-register-schema", false, fmt.Sprintf("Register the schema of --%s-schema-file if it is not registered yet", field))
+	command.Flags().String(field+"-proto-message", "", fmt.Sprintf("Full name of the Protobuf message of the %s, the first message of the schema by default", field))
+	command.MarkFlagsMutuallyExclusive(field+"-schema-version", field+"-schema-file")
+	command.MarkFlagsMutuallyExclusive(field+"-schema-version", field+"-register-schema")
+}
+
+// getSchemaSpec returns the schema of the key or value from the flags, or nil if it is not encoded with a schema
+func getSchemaSpec(command *cobra.Command, field string, topic string) (*serde.SchemaSpec, error) {
+	subject, err := command.Flags().GetString(field + "-schema-subject")
+	if err != nil {
+		return nil, err
+	}
+	version, err := command.Flags().GetInt(field + "-schema-version")
+	if err != nil {
+		return nil, err
+	}
+	file, err := command.Flags().GetString(field + "-schema-file")
+	if err != nil {
+		return nil, err
+	}
+	protoMessage, err := command.Flags().GetString(field + "-proto-message")
+	if err != nil {
+		return nil, err
+	}
+	register, err := command.Flags().GetBool(field + "-register-schema")
+	if err != nil {
+		return nil, err
+	}
+	if register && file == "" {
+		return nil, fmt.Errorf("--%s-schema-file is required to register the schema of the %s", field, field)
+	}
+	if subject == "" && file == "" {
+		if command.Flags().Changed(field+"-schema-version") || protoMessage != "" {
+			return nil, fmt.Errorf("--%s-schema-subject or --%s-schema-file is required to encode the %s with a schema", field, field, field)
+		}
+		return nil, nil
+	}
+	if subject == "" {
+		subject = topic + "-" + field
+	}
+	return &serde.SchemaSpec{Subject: subject, Version: version, File: file, Register: register, ProtoMessage: protoMessage}, nil
+}
+
+// newSchemaEncoder returns the encoder of spec, telling how to register the schema file if it is not registered
+func newSchemaEncoder(client *sr.Client, spec serde.SchemaSpec, field string) (*serde.SchemaEncoder, error) {
+	encoder, err := serde.NewSchemaEncoder(client, spec)
+	if errors.Is(err, serde.ErrSchemaNotRegistered) {
+		return nil, fmt.Errorf("%v. Register it with 'kacao create schema %s --file %s', or use --%s-register-schema", err, spec.Subject, spec.File, field)
+	}
+	return encoder, err
+}
+
+// encodeRecords encodes the non null keys and values of records with the schemas of the flags
+func encodeRecords(command *cobra.Command, topic string, records []*kgo.Record) error {
+	keySpec, err := getSchemaSpec(command, "key", topic)
+	if err != nil {
+		return err
+	}
+	valueSpec, err := getSchemaSpec(command, "value", topic)
+	if err != nil {
+		return err
+	}
+	if keySpec == nil && valueSpec == nil {
+		return nil
+	}
+
+	client, err := cmd.NewSchemaRegistryClient()
+	if err != nil {
+		return err
+	}
+	var keyEncoder, valueEncoder *serde.SchemaEncoder
+	if keySpec != nil {
+		if keyEncoder, err = newSchemaEncoder(client, *keySpec, "key"); err != nil {
+			return err
+		}
+	}
+	if valueSpec != nil {
+		if valueEncoder, err = newSchemaEncoder(client, *valueSpec, "value"); err != nil {
+			return err
+		}
+	}
+
+	for i, record := range records {
+		if keyEncoder != nil && record.Key != nil {
+			if record.Key, err = keyEncoder.Encode(record.Key); err != nil {
+				return fmt.Errorf("error encoding key of message %d: %v", i+1, err)
+			}
+		}
+		if valueEncoder != nil && record.Value != nil {
+			if record.Value, err = valueEncoder.Encode(record.Value); err != nil {
+				return fmt.Errorf("error encoding value of message %d: %v", i+1, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// quotaEntityTypes are the types of the components of client quota entities, in the order they are displayed
+var quotaEntityTypes = []string{"user", "client-id", "ip"}
+
+// AddQuotaEntityFlags adds the flags of the components of a client quota entity: a user, client id or ip, or their
+// default
+func AddQuotaEntityFlags(command *cobra.Command) {
+	command.Flags().String("user", "", "User of the quotas, example: --user alice")
+	command.Flags().String("client-id", "", "Client id of the quotas, example: --client-id billing-service")
+	command.Flags().String("ip", "", "IP of the quotas on connections, example: --ip 10.0.0.1")
+	command.Flags().Bool("default-user", false, "Use the default quotas of users, which apply to users without their own quotas")
+	command.Flags().Bool("default-client-id", false, "Use the default quotas of client ids, which apply to client ids without their own quotas")
+	command.Flags().Bool("default-ip", false, "Use the default quotas of IPs, which apply to IPs without their own quotas")
+	for _, entityType := range quotaEntityTypes {
+		command.MarkFlagsMutuallyExclusive(entityType, "default-"+entityType)
+	}
+}
+
+// GetQuotaEntity returns the client quota entity of the flags of AddQuotaEntityFlags, a nil name being the default of
+// the type
+func GetQuotaEntity(command *cobra.Command) (kadm.ClientQuotaEntity, error) {
+	var entity kadm.ClientQuotaEntity
+	for _, entityType := range quotaEntityTypes {
+		isDefault, err := command.Flags().GetBool("default-" + entityType)
+		cobra.CheckErr(err)
+		if isDefault {
+			entity = append(entity, kadm.ClientQuotaEntityComponent{Type: entityType})
+			continue
+		}
+		if !command.Flags().Changed(entityType) {
+			continue
+		}
+		name, err := command.Flags().GetString(entityType)
+		cobra.CheckErr(err)
+		if name == "" {
+			return nil, fmt.Errorf("invalid empty --%s, use --default-%s for the default quotas", entityType, entityType)
+		}
+		entity = append(entity, kadm.ClientQuotaEntityComponent{Type: entityType, Name: &name})
+	}
+	return entity, nil
+}
+
+// QuotaEntityFilter returns the components matching the entity to describe client quotas, exactly or by default
+func QuotaEntityFilter(entity kadm.ClientQuotaEntity) []kadm.DescribeClientQuotaComponent {
+	var components []kadm.DescribeClientQuotaComponent
+	for _, component := range entity {
+		if component.Name == nil {
+			components = append(components, kadm.DescribeClientQuotaComponent{Type: component.Type, MatchType: kmsg.QuotasMatchTypeDefault})
+			continue
+		}
+		components = append(components, kadm.DescribeClientQuotaComponent{Type: component.Type, MatchName: component.Name, MatchType: kmsg.QuotasMatchTypeExact})
+	}
+	return components
+}
+
+// FormatQuotaEntity renders a client quota entity, example: user=alice, client-id=<default>
+func FormatQuotaEntity(entity kadm.ClientQuotaEntity) string {
+	entity = slices.Clone(entity)
+	slices.SortFunc(entity, func(a, b kadm.ClientQuotaEntityComponent) int {
+		return slices.Index(quotaEntityTypes, a.Type) - slices.Index(quotaEntityTypes, b.Type)
+	})
+	components := make([]string, 0, len(entity))
+	for _, component := range entity {
+		components = append(components, component.String())
+	}
+	return strings.Join(components, ", ")
+}
+
+// FormatQuotaValue renders a quota without trailing zeros, example: 1048576
+func FormatQuotaValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// PrintQuotas prints a table of the quotas of client quota entities, sorted by entity and quota
+func PrintQuotas(out io.Writer, quotas kadm.DescribedClientQuotas) error {
+	type row struct {
+		entity string
+		quota  kadm.ClientQuotaValue
+	}
+	var rows []row
+	for _, quota := range quotas {
+		entity := FormatQuotaEntity(quota.Entity)
+		for _, value := range quota.Values {
+			rows = append(rows, row{entity: entity, quota: value})
+		}
+	}
+	slices.SortFunc(rows, func(a, b row) int {
+		if diff := strings.Compare(a.entity, b.entity); diff != 0 {
+			return diff
+		}
+		return strings.Compare(a.quota.Key, b.quota.Key)
+	})
+
+	_, err := fmt.Fprintf(out, "%-50s%-30s%-20s\n", "Entity", "Quota", "Value")
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		_, err := fmt.Fprintf(out, "%-50s%-30s%-20s\n", row.entity, row.quota.Key, FormatQuotaValue(row.quota.Value))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestGetQuotaEntity(t *testing.T) {
+	alice := "alice"
+	billing := "billing-service"
+	ip := "10.0.0.1"
+
+	tests := []struct {
+		name           string
+		flags          map[string]string
+		expectedEntity kadm.ClientQuotaEntity
+		expectedError  string
+	}{
+		{
+			name:           "no entity",
+			expectedEntity: nil,
+		},
+		{
+			name:           "user",
+			flags:          map[string]string{"user": "alice"},
+			expectedEntity: kadm.ClientQuotaEntity{{Type: "user", Name: &alice}},
+		},
+		{
+			name:           "user and client id",
+			flags:          map[string]string{"client-id": "billing-service", "user": "alice"},
+			expectedEntity: kadm.ClientQuotaEntity{{Type: "user", Name: &alice}, {Type: "client-id", Name: &billing}},
+		},
+		{
+			name:           "client id of the default user",
+			flags:          map[string]string{"default-user": "true", "client-id": "billing-service"},
+			expectedEntity: kadm.ClientQuotaEntity{{Type: "user"}, {Type: "client-id", Name: &billing}},
+		},
+		{
+			name:           "ip",
+			flags:          map[string]string{"ip": "10.0.0.1"},
+			expectedEntity: kadm.ClientQuotaEntity{{Type: "ip", Name: &ip}},
+		},
+		{
+			name:          "empty user",
+			flags:         map[string]string{"user": ""},
+			expectedError: "invalid empty --user, use --default-user for the default quotas",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := &cobra.Command{}
+			AddQuotaEntityFlags(command)
+			for flag, value := range tt.flags {
+				assert.NoError(t, command.Flags().Set(flag, value))
+			}
+
+			entity, err := GetQuotaEntity(command)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedEntity, entity)
+		})
+	}
+}
+
+func TestQuotaEntityFilter(t *testing.T) {
+	alice := "alice"
+	assert.Equal(t, []kadm.DescribeClientQuotaComponent{
+		{Type: "user", MatchName: &alice, MatchType: kmsg.QuotasMatchTypeExact},
+		{Type: "client-id", MatchType: kmsg.QuotasMatchTypeDefault},
+	}, QuotaEntityFilter(kadm.ClientQuotaEntity{{Type: "user", Name: &alice}, {Type: "client-id"}}))
+	assert.Nil(t, QuotaEntityFilter(nil))
+}
+
+func TestFormatQuotaEntity(t *testing.T) {
+	alice := "alice"
+	assert.Equal(t, "user=alice, client-id=<default>", FormatQuotaEntity(kadm.ClientQuotaEntity{{Type: "client-id"}, {Type: "user", Name: &alice}}))
+	assert.Equal(t, "ip=<default>", FormatQuotaEntity(kadm.ClientQuotaEntity{{Type: "ip"}}))
+}
+
+func TestFormatQuotaValue(t *testing.T) {
+	assert.Equal(t, "1048576", FormatQuotaValue(1048576))
+	assert.Equal(t, "12.5", FormatQuotaValue(12.5))
+}
+
+func TestPrintQuotas(t *testing.T) {
+	alice := "alice"
+	billing := "billing-service"
+	quotas := kadm.DescribedClientQuotas{
+		{
+			Entity: kadm.ClientQuotaEntity{{Type: "user", Name: &alice}},
+			Values: kadm.ClientQuotaValues{{Key: "producer_byte_rate", Value: 1048576}, {Key: "consumer_byte_rate", Value: 2097152}},
+		},
+		{
+			Entity: kadm.ClientQuotaEntity{{Type: "client-id", Name: &billing}},
+			Values: kadm.ClientQuotaValues{{Key: "request_percentage", Value: 50}},
+		},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, PrintQuotas(&out, quotas))
+	assert.Regexp(t, regexp.MustCompile(`^Entity\s+Quota\s+Value\s*\n`+
+		`client-id=billing-service\s+request_percentage\s+50\s*\n`+
+		`user=alice\s+consumer_byte_rate\s+2097152\s*\n`+
+		`user=alice\s+producer_byte_rate\s+1048576\s*\n$`), out.String())
+}
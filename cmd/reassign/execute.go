@@ -0,0 +1,180 @@
+package reassign
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var executeCmd = &cobra.Command{
+	Use:   "execute --plan <plan.json> [--throttle <bytes_per_second>]",
+	Short: "Start reassigning the replicas of partitions to the brokers of a plan",
+	Long: `Start reassigning the replicas of the partitions of a plan to its brokers.
+
+The replicas are copied to their new brokers in the background. The copy can be throttled, in bytes per second, so that
+it does not starve the clients of the brokers:
+- kacao reassign execute --plan plan.json
+- kacao reassign execute --plan plan.json --throttle 10485760
+
+The throttle applies to the brokers which are copied from and to, and only to the replicas of the plan. These topics
+and brokers are recorded in the plan file, under "throttle". The throttle stays until it is removed by
+'kacao reassign status --plan plan.json' once no partition is being reassigned anymore. It is removed right away when
+throttling or starting the reassignment fails, or when none of the partitions of the plan can be reassigned. When only
+some of them fail, the throttle is kept for the others.`,
+	Args: cobra.NoArgs,
+	RunE: func(command *cobra.Command, args []string) error {
+		planFile, err := command.Flags().GetString("plan")
+		cobra.CheckErr(err)
+		throttle, err := command.Flags().GetInt64("throttle")
+		cobra.CheckErr(err)
+		if throttle < 0 {
+			return fmt.Errorf("invalid --throttle %d, it must be positive", throttle)
+		}
+		reassignment, err := readPlan(planFile)
+		if err != nil {
+			return err
+		}
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		ctx := context.Background()
+
+		metadata, err := describeTopics(ctx, adminClient, planTopics(reassignment))
+		if err != nil {
+			return err
+		}
+		current, err := currentReplicas(metadata, reassignment)
+		if err != nil {
+			return err
+		}
+		var brokerIDs []int32
+		for _, partition := range reassignment.Partitions {
+			for _, replica := range append(slices.Clone(current[partition.Topic][partition.Partition]), partition.Replicas...) {
+				if !slices.Contains(brokerIDs, replica) {
+					brokerIDs = append(brokerIDs, replica)
+				}
+			}
+		}
+		slices.Sort(brokerIDs)
+		if err := checkBrokers(metadata, brokerIDs); err != nil {
+			return err
+		}
+
+		if throttle > 0 {
+			// The throttle is recorded before it is set, so that a throttle set in part is removed too
+			addThrottle(&reassignment, planTopics(reassignment), brokerIDs)
+			if err := writePlan(planFile, reassignment); err != nil {
+				return err
+			}
+			if err := setThrottle(ctx, adminClient, reassignment, current, brokerIDs, throttle); err != nil {
+				return failExecute(ctx, command, adminClient, planFile, reassignment, err)
+			}
+			_, err = fmt.Fprintf(command.OutOrStdout(), "Throttled replication to %d bytes per second on brokers %s\n", throttle, formatReplicas(brokerIDs))
+			cobra.CheckErr(err)
+		}
+
+		var req kadm.AlterPartitionAssignmentsReq
+		for _, partition := range reassignment.Partitions {
+			req.Assign(partition.Topic, partition.Partition, partition.Replicas)
+		}
+		responses, err := adminClient.AlterPartitionAssignments(ctx, req)
+		if err != nil {
+			return failExecute(ctx, command, adminClient, planFile, reassignment, fmt.Errorf("error reassigning partitions: %v", err))
+		}
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-30s%-12s%-25s%-25s%s\n", "Topic", "Partition", "Current replicas", "Target replicas", "Result")
+		cobra.CheckErr(err)
+		failed := 0
+		for _, partition := range reassignment.Partitions {
+			result := "started"
+			response := responses[partition.Topic][partition.Partition]
+			if response.Err != nil {
+				result = fmt.Sprintf("%v: %s", response.Err, response.ErrMessage)
+				failed++
+			}
+			_, err := fmt.Fprintf(command.OutOrStdout(), "%-30s%-12d%-25s%-25s%s\n", partition.Topic, partition.Partition,
+				formatReplicas(current[partition.Topic][partition.Partition]), formatReplicas(partition.Replicas), result)
+			cobra.CheckErr(err)
+		}
+
+		if failed == len(reassignment.Partitions) {
+			return failExecute(ctx, command, adminClient, planFile, reassignment, fmt.Errorf("failed to reassign one or more partitions"))
+		}
+		if failed > 0 {
+			// The partitions which started are copied with the throttle, which status removes once they are done
+			_, err = fmt.Fprintf(command.OutOrStdout(), "Check the progress of the partitions which started with 'kacao reassign status --plan %s'\n", planFile)
+			cobra.CheckErr(err)
+			return fmt.Errorf("failed to reassign one or more partitions")
+		}
+		_, err = fmt.Fprintf(command.OutOrStdout(), "Check the progress of the reassignment with 'kacao reassign status --plan %s'\n", planFile)
+		cobra.CheckErr(err)
+		return nil
+	},
+}
+
+// failExecute removes the throttle recorded in the plan for a reassignment which did not start, before returning err.
+// 'kacao reassign status' only removes it once it sees partitions of the plan reassigned, which never happens then.
+func failExecute(ctx context.Context, command *cobra.Command, adminClient *kadm.Client, planFile string, reassignment plan, err error) error {
+	if reassignment.Throttle == nil {
+		return err
+	}
+	if removeErr := removeThrottle(ctx, adminClient, planFile, reassignment); removeErr != nil {
+		return fmt.Errorf("%v, and %v", err, removeErr)
+	}
+	_, printErr := fmt.Fprintf(command.OutOrStdout(), "Removed the replication throttle of the topics and brokers, as the reassignment failed\n")
+	cobra.CheckErr(printErr)
+	return err
+}
+
+// setThrottle throttles the replication of the replicas of the plan on the brokers
+func setThrottle(ctx context.Context, adminClient *kadm.Client, reassignment plan, current map[string]map[int32][]int32, brokerIDs []int32, throttle int64) error {
+	leaders, followers := throttledReplicas(reassignment, current)
+	for _, topic := range planTopics(reassignment) {
+		var configs []kadm.AlterConfig
+		if replicas, ok := leaders[topic]; ok {
+			configs = append(configs, kadm.AlterConfig{Op: kadm.SetConfig, Name: leaderThrottledReplicas, Value: &replicas})
+		}
+		if replicas, ok := followers[topic]; ok {
+			configs = append(configs, kadm.AlterConfig{Op: kadm.SetConfig, Name: followerThrottledReplicas, Value: &replicas})
+		}
+		responses, err := adminClient.AlterTopicConfigs(ctx, configs, topic)
+		if err == nil {
+			err = cmd.AlterConfigsError(responses)
+		}
+		if err != nil {
+			return fmt.Errorf("error throttling the replicas of topic '%s': %v", topic, err)
+		}
+	}
+
+	rate := strconv.FormatInt(throttle, 10)
+	responses, err := adminClient.AlterBrokerConfigs(ctx, []kadm.AlterConfig{
+		{Op: kadm.SetConfig, Name: leaderThrottledRate, Value: &rate},
+		{Op: kadm.SetConfig, Name: followerThrottledRate, Value: &rate},
+	}, brokerIDs...)
+	if err == nil {
+		err = cmd.AlterConfigsError(responses)
+	}
+	if err != nil {
+		return fmt.Errorf("error throttling the replication of brokers: %v", err)
+	}
+	return nil
+}
+
+func init() {
+	executeCmd.Flags().String("plan", "", "File of the plan, written by 'kacao reassign generate'")
+	executeCmd.Flags().Int64("throttle", 0, "Throttle the replication of the reassigned replicas, in bytes per second")
+	cobra.CheckErr(executeCmd.MarkFlagRequired("plan"))
+	reassignCmd.AddCommand(executeCmd)
+}
@@ -0,0 +1,115 @@
+package reassign
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate --topics <topic>[,<topic>...] --brokers <broker_id>[,<broker_id>...] --output <plan.json>",
+	Short: "Generate a plan to reassign the replicas of topics to brokers",
+	Long: `Generate a plan to reassign the replicas of the partitions of topics to brokers, and write it to a file.
+
+The replicas and the leaders are balanced across the brokers, keeping the replication factor of the partitions. Replicas
+stay on their broker while it is in --brokers and does not hold more than its share, so that only the replicas needed to
+balance the brokers are moved. The replicas of a partition are placed on distinct racks when the brokers have enough
+racks.
+
+To decommission broker 4, list the other brokers:
+- kacao reassign generate --topics orders,payments --brokers 1,2,3 --output plan.json
+
+The current and proposed replicas of the partitions are printed, the first replica being the preferred leader. Nothing
+is changed before the plan is executed with 'kacao reassign execute --plan plan.json'.`,
+	Args: cobra.NoArgs,
+	RunE: func(command *cobra.Command, args []string) error {
+		topics, err := command.Flags().GetStringSlice("topics")
+		cobra.CheckErr(err)
+		// A topic listed twice would add its partitions twice to the plan
+		slices.Sort(topics)
+		topics = slices.Compact(topics)
+		brokerIDs, err := command.Flags().GetInt32Slice("brokers")
+		cobra.CheckErr(err)
+		output, err := command.Flags().GetString("output")
+		cobra.CheckErr(err)
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		metadata, err := describeTopics(context.Background(), adminClient, topics)
+		if err != nil {
+			return err
+		}
+		if err := checkBrokers(metadata, brokerIDs); err != nil {
+			return err
+		}
+
+		var brokers []planBroker
+		for _, broker := range metadata.Brokers {
+			if !slices.Contains(brokerIDs, broker.NodeID) {
+				continue
+			}
+			rack := ""
+			if broker.Rack != nil {
+				rack = *broker.Rack
+			}
+			brokers = append(brokers, planBroker{id: broker.NodeID, rack: rack})
+		}
+		var current []planPartition
+		for _, topic := range topics {
+			for _, partitionDetail := range metadata.Topics[topic].Partitions.Sorted() {
+				current = append(current, planPartition{Topic: topic, Partition: partitionDetail.Partition, Replicas: partitionDetail.Replicas})
+			}
+		}
+
+		proposed, err := generatePlan(current, brokers)
+		if err != nil {
+			return err
+		}
+		if err := writePlan(output, proposed); err != nil {
+			return err
+		}
+
+		currentByPartition := make(map[string][]int32)
+		for _, partition := range current {
+			currentByPartition[fmt.Sprintf("%s-%d", partition.Topic, partition.Partition)] = partition.Replicas
+		}
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-30s%-12s%-25s%-25s\n", "Topic", "Partition", "Current replicas", "Proposed replicas")
+		cobra.CheckErr(err)
+		moved := 0
+		for _, partition := range proposed.Partitions {
+			currentReplicas := currentByPartition[fmt.Sprintf("%s-%d", partition.Topic, partition.Partition)]
+			for _, replica := range partition.Replicas {
+				if !slices.Contains(currentReplicas, replica) {
+					moved++
+				}
+			}
+			_, err := fmt.Fprintf(command.OutOrStdout(), "%-30s%-12d%-25s%-25s\n", partition.Topic, partition.Partition,
+				formatReplicas(currentReplicas), formatReplicas(partition.Replicas))
+			cobra.CheckErr(err)
+		}
+		_, err = fmt.Fprintf(command.OutOrStdout(), "Wrote plan of %d partition(s), moving %d replica(s), to '%s'\n", len(proposed.Partitions), moved, output)
+		cobra.CheckErr(err)
+		return nil
+	},
+}
+
+func init() {
+	generateCmd.Flags().StringSlice("topics", []string{}, "Topics whose partitions are reassigned")
+	generateCmd.Flags().Int32Slice("brokers", []int32{}, "Brokers to assign the replicas to")
+	generateCmd.Flags().StringP("output", "o", "", "File to write the plan to")
+	for _, flag := range []string{"topics", "brokers", "output"} {
+		cobra.CheckErr(generateCmd.MarkFlagRequired(flag))
+	}
+	reassignCmd.AddCommand(generateCmd)
+}
@@ -0,0 +1,256 @@
+package reassign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// plan is a reassignment of the replicas of partitions, in the JSON format of kafka-reassign-partitions.sh
+type plan struct {
+	Version    int             `json:"version"`
+	Partitions []planPartition `json:"partitions"`
+	// Throttle is recorded by 'kacao reassign execute' when it throttles the replication, so that only that throttle
+	// is removed afterwards
+	Throttle *planThrottle `json:"throttle,omitempty"`
+}
+
+// planThrottle is the topics whose replicas are throttled, and the brokers whose replication rate is throttled
+type planThrottle struct {
+	Topics  []string `json:"topics"`
+	Brokers []int32  `json:"brokers"`
+}
+
+// planPartition is the replicas of a partition, the first one being its preferred leader
+type planPartition struct {
+	Topic     string  `json:"topic"`
+	Partition int32   `json:"partition"`
+	Replicas  []int32 `json:"replicas"`
+}
+
+// planBroker is a broker which replicas can be assigned to, with its rack or an empty rack
+type planBroker struct {
+	id   int32
+	rack string
+}
+
+func readPlan(path string) (plan, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return plan{}, fmt.Errorf("error reading plan '%s': %v", path, err)
+	}
+	var reassignment plan
+	if err := json.Unmarshal(content, &reassignment); err != nil {
+		return plan{}, fmt.Errorf("invalid plan '%s': %v", path, err)
+	}
+	if len(reassignment.Partitions) == 0 {
+		return plan{}, fmt.Errorf("invalid plan '%s': no partition to reassign", path)
+	}
+	seen := make(map[string]bool)
+	for _, partition := range reassignment.Partitions {
+		key := fmt.Sprintf("%s-%d", partition.Topic, partition.Partition)
+		if seen[key] {
+			return plan{}, fmt.Errorf("invalid plan '%s': partition %d of topic '%s' is assigned twice", path, partition.Partition, partition.Topic)
+		}
+		seen[key] = true
+		if len(partition.Replicas) == 0 {
+			return plan{}, fmt.Errorf("invalid plan '%s': no replica for partition %d of topic '%s'", path, partition.Partition, partition.Topic)
+		}
+		for i, replica := range partition.Replicas {
+			if slices.Contains(partition.Replicas[:i], replica) {
+				return plan{}, fmt.Errorf("invalid plan '%s': broker %d is assigned twice to partition %d of topic '%s'", path, replica, partition.Partition, partition.Topic)
+			}
+		}
+	}
+	return reassignment, nil
+}
+
+// addThrottle records the throttle of topics and brokers in the plan, keeping the throttle recorded by a previous
+// execution which has not been removed yet
+func addThrottle(reassignment *plan, topics []string, brokerIDs []int32) {
+	if reassignment.Throttle == nil {
+		reassignment.Throttle = &planThrottle{}
+	}
+	for _, topic := range topics {
+		if !slices.Contains(reassignment.Throttle.Topics, topic) {
+			reassignment.Throttle.Topics = append(reassignment.Throttle.Topics, topic)
+		}
+	}
+	for _, brokerID := range brokerIDs {
+		if !slices.Contains(reassignment.Throttle.Brokers, brokerID) {
+			reassignment.Throttle.Brokers = append(reassignment.Throttle.Brokers, brokerID)
+		}
+	}
+	slices.Sort(reassignment.Throttle.Brokers)
+}
+
+func writePlan(path string, reassignment plan) error {
+	content, err := json.MarshalIndent(reassignment, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing plan '%s': %v", path, err)
+	}
+	return nil
+}
+
+// generatePlan assigns the replicas of the partitions to the brokers, keeping their replication factor. Replicas stay
+// on their broker while it is a target and holds no more than its share of replicas, so that only the replicas
+// needed to balance the brokers are moved. The replicas of a partition are placed on distinct racks when there are
+// enough racks, and the leaders are balanced too.
+func generatePlan(current []planPartition, brokers []planBroker) (plan, error) {
+	if len(brokers) == 0 {
+		return plan{}, errors.New("no broker to assign replicas to")
+	}
+	brokers = slices.Clone(brokers)
+	slices.SortFunc(brokers, func(a, b planBroker) int { return int(a.id) - int(b.id) })
+	current = slices.Clone(current)
+	slices.SortFunc(current, func(a, b planPartition) int {
+		if diff := strings.Compare(a.Topic, b.Topic); diff != 0 {
+			return diff
+		}
+		return int(a.Partition) - int(b.Partition)
+	})
+
+	racks := make(map[int32]string)
+	rackCount := 0
+	seenRacks := make(map[string]bool)
+	for _, broker := range brokers {
+		racks[broker.id] = broker.rack
+		if broker.rack == "" {
+			rackCount++
+		} else if !seenRacks[broker.rack] {
+			seenRacks[broker.rack] = true
+			rackCount++
+		}
+	}
+
+	totalReplicas := 0
+	for _, partition := range current {
+		if len(partition.Replicas) > len(brokers) {
+			return plan{}, fmt.Errorf("partition %d of topic '%s' has %d replicas, more than the %d brokers", partition.Partition, partition.Topic, len(partition.Replicas), len(brokers))
+		}
+		totalReplicas += len(partition.Replicas)
+	}
+	maxReplicas := (totalReplicas + len(brokers) - 1) / len(brokers)
+	maxLeaders := (len(current) + len(brokers) - 1) / len(brokers)
+
+	// The replicas which stay on their broker are chosen for all the partitions first, so that the brokers which are
+	// filled up to their share are those which already hold the replicas
+	replicaCounts := make(map[int32]int)
+	assigned := make([][]int32, len(current))
+	for i, partition := range current {
+		counter := newRackCounter(racks, rackCount, len(partition.Replicas))
+		for _, replica := range partition.Replicas {
+			if _, ok := racks[replica]; ok && replicaCounts[replica] < maxReplicas && counter.available(replica) {
+				assigned[i] = append(assigned[i], replica)
+				counter.add(replica)
+				replicaCounts[replica]++
+			}
+		}
+	}
+
+	leaderCounts := make(map[int32]int)
+	proposed := plan{Version: 1}
+	for i, partition := range current {
+		replicas := assigned[i]
+		counter := newRackCounter(racks, rackCount, len(partition.Replicas))
+		for _, replica := range replicas {
+			counter.add(replica)
+		}
+		for len(replicas) < len(partition.Replicas) {
+			best := int32(-1)
+			bestAvailable := false
+			for _, broker := range brokers {
+				if slices.Contains(replicas, broker.id) {
+					continue
+				}
+				available := counter.available(broker.id)
+				if best == -1 || available && !bestAvailable || available == bestAvailable && replicaCounts[broker.id] < replicaCounts[best] {
+					best, bestAvailable = broker.id, available
+				}
+			}
+			replicas = append(replicas, best)
+			counter.add(best)
+			replicaCounts[best]++
+		}
+
+		// The current leader stays the preferred leader while it holds no more than its share of leaders
+		leader := slices.IndexFunc(replicas, func(id int32) bool { return id == partition.Replicas[0] && leaderCounts[id] < maxLeaders })
+		if leader < 0 {
+			leader = 0
+			for j, replica := range replicas {
+				if leaderCounts[replica] < leaderCounts[replicas[leader]] {
+					leader = j
+				}
+			}
+		}
+		replicas[0], replicas[leader] = replicas[leader], replicas[0]
+		leaderCounts[replicas[0]]++
+		proposed.Partitions = append(proposed.Partitions, planPartition{Topic: partition.Topic, Partition: partition.Partition, Replicas: replicas})
+	}
+	return proposed, nil
+}
+
+// rackCounter spreads the replicas of a partition across racks: a rack holds at most its share of the replicas,
+// brokers without a rack being alone in their rack
+type rackCounter struct {
+	racks    map[int32]string
+	counts   map[string]int
+	maxCount int
+}
+
+func newRackCounter(racks map[int32]string, rackCount int, replicationFactor int) *rackCounter {
+	return &rackCounter{racks: racks, counts: make(map[string]int), maxCount: (replicationFactor + rackCount - 1) / rackCount}
+}
+
+func (c *rackCounter) available(id int32) bool {
+	return c.racks[id] == "" || c.counts[c.racks[id]] < c.maxCount
+}
+
+func (c *rackCounter) add(id int32) {
+	if c.racks[id] != "" {
+		c.counts[c.racks[id]]++
+	}
+}
+
+// throttledReplicas returns the values of the leader.replication.throttled.replicas and
+// follower.replication.throttled.replicas configs of the topics of the plan: the replicas which are copied from, and
+// the replicas which are added, as partition:broker lists
+func throttledReplicas(reassignment plan, current map[string]map[int32][]int32) (leaders map[string]string, followers map[string]string) {
+	leaderReplicas := make(map[string][]string)
+	followerReplicas := make(map[string][]string)
+	for _, partition := range reassignment.Partitions {
+		currentReplicas := current[partition.Topic][partition.Partition]
+		for _, replica := range currentReplicas {
+			leaderReplicas[partition.Topic] = append(leaderReplicas[partition.Topic], fmt.Sprintf("%d:%d", partition.Partition, replica))
+		}
+		for _, replica := range partition.Replicas {
+			if !slices.Contains(currentReplicas, replica) {
+				followerReplicas[partition.Topic] = append(followerReplicas[partition.Topic], fmt.Sprintf("%d:%d", partition.Partition, replica))
+			}
+		}
+	}
+	leaders = make(map[string]string)
+	followers = make(map[string]string)
+	for topic, replicas := range leaderReplicas {
+		leaders[topic] = strings.Join(replicas, ",")
+	}
+	for topic, replicas := range followerReplicas {
+		followers[topic] = strings.Join(replicas, ",")
+	}
+	return leaders, followers
+}
+
+func formatReplicas(replicas []int32) string {
+	ids := make([]string, 0, len(replicas))
+	for _, replica := range replicas {
+		ids = append(ids, strconv.Itoa(int(replica)))
+	}
+	return "[" + strings.Join(ids, ",") + "]"
+}
@@ -0,0 +1,178 @@
+package reassign
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratePlan(t *testing.T) {
+	tests := []struct {
+		name             string
+		current          []planPartition
+		brokers          []planBroker
+		expectedReplicas [][]int32
+		expectedError    string
+	}{
+		{
+			name: "decommission a broker",
+			current: []planPartition{
+				{Topic: "orders", Partition: 0, Replicas: []int32{1, 2}},
+				{Topic: "orders", Partition: 1, Replicas: []int32{2, 3}},
+				{Topic: "orders", Partition: 2, Replicas: []int32{3, 4}},
+				{Topic: "orders", Partition: 3, Replicas: []int32{4, 1}},
+			},
+			brokers: []planBroker{{id: 1}, {id: 2}, {id: 3}},
+			// Only the replicas of broker 4 move, to the brokers with the fewest replicas
+			expectedReplicas: [][]int32{{1, 2}, {2, 3}, {3, 1}, {1, 2}},
+		},
+		{
+			name: "balance a new broker",
+			current: []planPartition{
+				{Topic: "orders", Partition: 0, Replicas: []int32{1}},
+				{Topic: "orders", Partition: 1, Replicas: []int32{1}},
+				{Topic: "orders", Partition: 2, Replicas: []int32{2}},
+				{Topic: "orders", Partition: 3, Replicas: []int32{2}},
+			},
+			brokers:          []planBroker{{id: 1}, {id: 2}, {id: 3}, {id: 4}},
+			expectedReplicas: [][]int32{{1}, {3}, {2}, {4}},
+		},
+		{
+			name: "partitions are sorted by topic and partition",
+			current: []planPartition{
+				{Topic: "payments", Partition: 0, Replicas: []int32{1}},
+				{Topic: "orders", Partition: 1, Replicas: []int32{1}},
+				{Topic: "orders", Partition: 0, Replicas: []int32{1}},
+			},
+			brokers:          []planBroker{{id: 2}, {id: 1}},
+			expectedReplicas: [][]int32{{1}, {1}, {2}},
+		},
+		{
+			name: "replicas on distinct racks",
+			current: []planPartition{
+				{Topic: "orders", Partition: 0, Replicas: []int32{1, 2}},
+				{Topic: "orders", Partition: 1, Replicas: []int32{3, 4}},
+			},
+			brokers: []planBroker{{id: 1, rack: "a"}, {id: 2, rack: "a"}, {id: 3, rack: "b"}, {id: 4, rack: "b"}},
+			// Broker 2 is in the rack of broker 1, broker 4 in the rack of broker 3
+			expectedReplicas: [][]int32{{1, 4}, {3, 2}},
+		},
+		{
+			name: "racks are reused when there are fewer racks than replicas",
+			current: []planPartition{
+				{Topic: "orders", Partition: 0, Replicas: []int32{1, 2, 3}},
+			},
+			brokers:          []planBroker{{id: 1, rack: "a"}, {id: 2, rack: "a"}, {id: 3, rack: "b"}},
+			expectedReplicas: [][]int32{{1, 2, 3}},
+		},
+		{
+			name: "leaders are balanced",
+			current: []planPartition{
+				{Topic: "orders", Partition: 0, Replicas: []int32{1, 2}},
+				{Topic: "orders", Partition: 1, Replicas: []int32{1, 2}},
+			},
+			brokers:          []planBroker{{id: 1}, {id: 2}},
+			expectedReplicas: [][]int32{{1, 2}, {2, 1}},
+		},
+		{
+			name: "more replicas than brokers",
+			current: []planPartition{
+				{Topic: "orders", Partition: 0, Replicas: []int32{1, 2, 3}},
+			},
+			brokers:       []planBroker{{id: 1}, {id: 2}},
+			expectedError: "partition 0 of topic 'orders' has 3 replicas, more than the 2 brokers",
+		},
+		{
+			name:          "no broker",
+			current:       []planPartition{{Topic: "orders", Partition: 0, Replicas: []int32{1}}},
+			expectedError: "no broker to assign replicas to",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proposed, err := generatePlan(tt.current, tt.brokers)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, proposed.Version)
+			var replicas [][]int32
+			for _, partition := range proposed.Partitions {
+				replicas = append(replicas, partition.Replicas)
+			}
+			assert.Equal(t, tt.expectedReplicas, replicas)
+		})
+	}
+}
+
+func TestReadPlan(t *testing.T) {
+	tempDir := t.TempDir()
+	planFile := filepath.Join(tempDir, "plan.json")
+	reassignment := plan{Version: 1, Partitions: []planPartition{{Topic: "orders", Partition: 0, Replicas: []int32{2, 3}}}}
+	assert.NoError(t, writePlan(planFile, reassignment))
+	content, err := os.ReadFile(planFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"topic": "orders"`)
+
+	read, err := readPlan(planFile)
+	assert.NoError(t, err)
+	assert.Equal(t, reassignment, read)
+
+	reassignment.Throttle = &planThrottle{Topics: []string{"orders"}, Brokers: []int32{2, 3}}
+	assert.NoError(t, writePlan(planFile, reassignment))
+	read, err = readPlan(planFile)
+	assert.NoError(t, err)
+	assert.Equal(t, reassignment, read)
+
+	invalidPlans := map[string]string{
+		`{"version":1,"partitions":[]}`: "no partition to reassign",
+		`{"version":1,"partitions":[{"topic":"orders","partition":0,"replicas":[1]},{"topic":"orders","partition":0,"replicas":[2]}]}`: "partition 0 of topic 'orders' is assigned twice",
+		`{"version":1,"partitions":[{"topic":"orders","partition":0,"replicas":[]}]}`:                                                  "no replica for partition 0 of topic 'orders'",
+		`{"version":1,"partitions":[{"topic":"orders","partition":0,"replicas":[1,1]}]}`:                                               "broker 1 is assigned twice to partition 0 of topic 'orders'",
+	}
+	for content, expectedError := range invalidPlans {
+		assert.NoError(t, os.WriteFile(planFile, []byte(content), 0o644))
+		_, err := readPlan(planFile)
+		assert.EqualError(t, err, "invalid plan '"+planFile+"': "+expectedError)
+	}
+
+	_, err = readPlan(filepath.Join(tempDir, "missing.json"))
+	assert.ErrorContains(t, err, "error reading plan")
+}
+
+func TestThrottledReplicas(t *testing.T) {
+	reassignment := plan{Version: 1, Partitions: []planPartition{
+		{Topic: "orders", Partition: 0, Replicas: []int32{1, 3}},
+		{Topic: "orders", Partition: 1, Replicas: []int32{2, 3}},
+		{Topic: "payments", Partition: 0, Replicas: []int32{1}},
+	}}
+	current := map[string]map[int32][]int32{
+		"orders":   {0: {1, 2}, 1: {2, 3}},
+		"payments": {0: {1}},
+	}
+
+	leaders, followers := throttledReplicas(reassignment, current)
+	assert.Equal(t, map[string]string{"orders": "0:1,0:2,1:2,1:3", "payments": "0:1"}, leaders)
+	assert.Equal(t, map[string]string{"orders": "0:3"}, followers)
+}
+
+func TestAddThrottle(t *testing.T) {
+	var reassignment plan
+	addThrottle(&reassignment, []string{"orders", "payments"}, []int32{3, 1})
+	assert.Equal(t, &planThrottle{Topics: []string{"orders", "payments"}, Brokers: []int32{1, 3}}, reassignment.Throttle)
+
+	// The throttle of a previous execution is kept
+	addThrottle(&reassignment, []string{"orders", "users"}, []int32{2, 3})
+	assert.Equal(t, &planThrottle{Topics: []string{"orders", "payments", "users"}, Brokers: []int32{1, 2, 3}}, reassignment.Throttle)
+}
+
+func TestPartitionStatus(t *testing.T) {
+	assert.Equal(t, statusCompleted, partitionStatus([]int32{1, 2}, []int32{1, 2}))
+	assert.Equal(t, statusCompleted, partitionStatus([]int32{2, 1}, []int32{1, 2}))
+	assert.Equal(t, statusNotApplied, partitionStatus([]int32{1, 3}, []int32{1, 2}))
+	assert.Equal(t, statusNotApplied, partitionStatus([]int32{1, 2, 3}, []int32{1, 2}))
+}
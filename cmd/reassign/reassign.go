@@ -0,0 +1,88 @@
+package reassign
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+var reassignCmd = &cobra.Command{
+	Use:   "reassign",
+	Short: "Reassign the replicas of partitions to brokers",
+	Long: `Reassign the replicas of partitions to brokers, to balance them or to decommission brokers.
+
+A plan is generated for topics and the brokers to move their replicas to, then executed, and its progress followed
+until it completes:
+- kacao reassign generate --topics orders,payments --brokers 1,2,3 --output plan.json
+- kacao reassign execute --plan plan.json --throttle 10485760
+- kacao reassign status --plan plan.json
+
+Plans are in the JSON format of kafka-reassign-partitions.sh.`,
+}
+
+// describeTopics returns the metadata of the brokers and of the topics, which must exist
+func describeTopics(ctx context.Context, adminClient *kadm.Client, topics []string) (kadm.Metadata, error) {
+	metadata, err := adminClient.Metadata(ctx, topics...)
+	if err != nil {
+		return kadm.Metadata{}, fmt.Errorf("error describing topics: %v", err)
+	}
+	for _, topic := range topics {
+		topicDetail, ok := metadata.Topics[topic]
+		if !ok || topicDetail.Err != nil {
+			return kadm.Metadata{}, fmt.Errorf("topic '%s' does not exist in the cluster", topic)
+		}
+	}
+	return metadata, nil
+}
+
+// checkBrokers returns an error if a broker is not in the cluster
+func checkBrokers(metadata kadm.Metadata, brokerIDs []int32) error {
+	for _, brokerID := range brokerIDs {
+		if !slices.ContainsFunc(metadata.Brokers, func(broker kadm.BrokerDetail) bool { return broker.NodeID == brokerID }) {
+			return fmt.Errorf("broker %d does not exist in the cluster", brokerID)
+		}
+	}
+	return nil
+}
+
+// planTopics returns the topics of the plan, in their order in the plan
+func planTopics(reassignment plan) []string {
+	var topics []string
+	for _, partition := range reassignment.Partitions {
+		if !slices.Contains(topics, partition.Topic) {
+			topics = append(topics, partition.Topic)
+		}
+	}
+	return topics
+}
+
+// currentReplicas returns the replicas of the partitions of the plan, which must exist
+func currentReplicas(metadata kadm.Metadata, reassignment plan) (map[string]map[int32][]int32, error) {
+	current := make(map[string]map[int32][]int32)
+	for _, partition := range reassignment.Partitions {
+		partitionDetail, ok := metadata.Topics[partition.Topic].Partitions[partition.Partition]
+		if !ok {
+			return nil, fmt.Errorf("partition %d of topic '%s' does not exist", partition.Partition, partition.Topic)
+		}
+		if current[partition.Topic] == nil {
+			current[partition.Topic] = make(map[int32][]int32)
+		}
+		current[partition.Topic][partition.Partition] = partitionDetail.Replicas
+	}
+	return current, nil
+}
+
+const (
+	leaderThrottledReplicas   = "leader.replication.throttled.replicas"
+	followerThrottledReplicas = "follower.replication.throttled.replicas"
+	leaderThrottledRate       = "leader.replication.throttled.rate"
+	followerThrottledRate     = "follower.replication.throttled.rate"
+)
+
+func init() {
+	cmd.RootCmd.AddCommand(reassignCmd)
+}
@@ -0,0 +1,151 @@
+package reassign
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestReassign(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	_, err = adminClient.CreateTopic(ctx, 2, 1, nil, "orders")
+	assert.NoError(t, err)
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+
+	// The steps run in order, each one using the plan of the previous ones
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+	}{
+		{
+			name: "generate a plan",
+			args: []string{"reassign", "generate", "--topics", "orders", "--brokers", "1", "--output", planFile},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Topic\s+Partition\s+Current replicas\s+Proposed replicas\s+\n`),
+				regexp.MustCompile(`\norders\s+0\s+\[1\]\s+\[1\]\s+\n`),
+				regexp.MustCompile(`\norders\s+1\s+\[1\]\s+\[1\]\s+\n`),
+				regexp.MustCompile(`\nWrote plan of 2 partition\(s\), moving 0 replica\(s\), to '` + regexp.QuoteMeta(planFile) + `'\n$`),
+			},
+		},
+		{
+			name: "generate a plan of a topic listed twice",
+			args: []string{"reassign", "generate", "--topics", "orders,orders", "--brokers", "1", "--output", planFile},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`\nWrote plan of 2 partition\(s\), moving 0 replica\(s\), to '` + regexp.QuoteMeta(planFile) + `'\n$`),
+			},
+		},
+		{
+			name:          "generate a plan to an unknown broker",
+			args:          []string{"reassign", "generate", "--topics", "orders", "--brokers", "1,5", "--output", planFile},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: broker 5 does not exist in the cluster`),
+			},
+		},
+		{
+			name:          "generate a plan of an unknown topic",
+			args:          []string{"reassign", "generate", "--topics", "unknown", "--brokers", "1", "--output", planFile},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: topic 'unknown' does not exist in the cluster`),
+			},
+		},
+		{
+			name: "execute the plan with a throttle",
+			args: []string{"reassign", "execute", "--plan", planFile, "--throttle", "1048576"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Throttled replication to 1048576 bytes per second on brokers \[1\]\n`),
+				regexp.MustCompile(`\norders\s+0\s+\[1\]\s+\[1\]\s+started\n`),
+				regexp.MustCompile(`\norders\s+1\s+\[1\]\s+\[1\]\s+started\n`),
+				regexp.MustCompile(`\nCheck the progress of the reassignment with 'kacao reassign status --plan ` + regexp.QuoteMeta(planFile) + `'\n$`),
+			},
+		},
+		{
+			name: "status of the plan",
+			args: []string{"reassign", "status", "--plan", planFile},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Topic\s+Partition\s+Replicas\s+Target replicas\s+Adding\s+Removing\s+Status\n`),
+				regexp.MustCompile(`\norders\s+0\s+\[1\]\s+\[1\]\s+-\s+-\s+completed\n`),
+				regexp.MustCompile(`\n2 of 2 partition\(s\) reassigned\nRemoved the replication throttle of the topics and brokers\n$`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+		})
+	}
+
+	// The throttle recorded by execute is removed from the plan with the configs
+	reassignment, err := readPlan(planFile)
+	assert.NoError(t, err)
+	assert.Nil(t, reassignment.Throttle)
+
+	configs, err := adminClient.DescribeTopicConfigs(ctx, "orders")
+	assert.NoError(t, err)
+	for _, config := range configs[0].Configs {
+		if config.Key == leaderThrottledReplicas || config.Key == followerThrottledReplicas {
+			assert.Empty(t, config.MaybeValue())
+		}
+	}
+}
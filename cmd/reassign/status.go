@@ -0,0 +1,148 @@
+package reassign
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	statusCompleted  = "completed"
+	statusInProgress = "in progress"
+	statusNotApplied = "not applied"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status --plan <plan.json> [--keep-throttle]",
+	Short: "Display the progress of the reassignment of a plan",
+	Long: `Display the progress of the reassignment of the partitions of a plan.
+
+Each partition is:
+- in progress: its replicas are being copied to their new brokers, which are listed in Adding, and will be removed from
+  the brokers listed in Removing
+- completed: its replicas are on the brokers of the plan
+- not applied: its replicas are on other brokers and it is not being reassigned, the plan was not executed or its
+  reassignment was cancelled
+
+Once no partition is in progress anymore, the replication throttle set by 'kacao reassign execute' is removed from the
+topics and brokers recorded in the plan, unless --keep-throttle is set:
+- kacao reassign status --plan plan.json`,
+	Args: cobra.NoArgs,
+	RunE: func(command *cobra.Command, args []string) error {
+		planFile, err := command.Flags().GetString("plan")
+		cobra.CheckErr(err)
+		keepThrottle, err := command.Flags().GetBool("keep-throttle")
+		cobra.CheckErr(err)
+		reassignment, err := readPlan(planFile)
+		if err != nil {
+			return err
+		}
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		ctx := context.Background()
+
+		var partitions kadm.TopicsSet
+		for _, partition := range reassignment.Partitions {
+			partitions.Add(partition.Topic, partition.Partition)
+		}
+		reassigning, err := adminClient.ListPartitionReassignments(ctx, partitions)
+		if err != nil {
+			return fmt.Errorf("error listing partition reassignments: %v", err)
+		}
+		topics := planTopics(reassignment)
+		metadata, err := describeTopics(ctx, adminClient, topics)
+		if err != nil {
+			return err
+		}
+		current, err := currentReplicas(metadata, reassignment)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-30s%-12s%-25s%-25s%-15s%-15s%s\n", "Topic", "Partition", "Replicas", "Target replicas", "Adding", "Removing", "Status")
+		cobra.CheckErr(err)
+		completed, inProgress := 0, 0
+		for _, partition := range reassignment.Partitions {
+			replicas := current[partition.Topic][partition.Partition]
+			adding, removing := "-", "-"
+			status := partitionStatus(replicas, partition.Replicas)
+			if progress, ok := reassigning[partition.Topic][partition.Partition]; ok {
+				status = statusInProgress
+				adding, removing = formatReplicas(progress.AddingReplicas), formatReplicas(progress.RemovingReplicas)
+				inProgress++
+			}
+			if status == statusCompleted {
+				completed++
+			}
+			_, err := fmt.Fprintf(command.OutOrStdout(), "%-30s%-12d%-25s%-25s%-15s%-15s%s\n", partition.Topic, partition.Partition,
+				formatReplicas(replicas), formatReplicas(partition.Replicas), adding, removing, status)
+			cobra.CheckErr(err)
+		}
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%d of %d partition(s) reassigned\n", completed, len(reassignment.Partitions))
+		cobra.CheckErr(err)
+
+		// Partitions which failed to start are never reassigned, so the throttle is removed once nothing is copied
+		if inProgress > 0 || keepThrottle || reassignment.Throttle == nil {
+			return nil
+		}
+		if err := removeThrottle(ctx, adminClient, planFile, reassignment); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(command.OutOrStdout(), "Removed the replication throttle of the topics and brokers\n")
+		cobra.CheckErr(err)
+		return nil
+	},
+}
+
+// partitionStatus tells whether a partition which is not being reassigned has the replicas of the plan, whatever their
+// order
+func partitionStatus(replicas []int32, target []int32) string {
+	if len(replicas) != len(target) {
+		return statusNotApplied
+	}
+	for _, replica := range target {
+		if !slices.Contains(replicas, replica) {
+			return statusNotApplied
+		}
+	}
+	return statusCompleted
+}
+
+// removeThrottle deletes the throttle configs recorded in the plan from its topics and brokers, then removes the
+// record from the plan file
+func removeThrottle(ctx context.Context, adminClient *kadm.Client, planFile string, reassignment plan) error {
+	responses, err := adminClient.AlterTopicConfigs(ctx, []kadm.AlterConfig{
+		{Op: kadm.DeleteConfig, Name: leaderThrottledReplicas},
+		{Op: kadm.DeleteConfig, Name: followerThrottledReplicas},
+	}, reassignment.Throttle.Topics...)
+	if err == nil {
+		err = cmd.AlterConfigsError(responses)
+	}
+	if err != nil {
+		return fmt.Errorf("error removing the replication throttle of topics: %v", err)
+	}
+	responses, err = adminClient.AlterBrokerConfigs(ctx, []kadm.AlterConfig{
+		{Op: kadm.DeleteConfig, Name: leaderThrottledRate},
+		{Op: kadm.DeleteConfig, Name: followerThrottledRate},
+	}, reassignment.Throttle.Brokers...)
+	if err == nil {
+		err = cmd.AlterConfigsError(responses)
+	}
+	if err != nil {
+		return fmt.Errorf("error removing the replication throttle of brokers: %v", err)
+	}
+	reassignment.Throttle = nil
+	return writePlan(planFile, reassignment)
+}
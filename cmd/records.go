@@ -0,0 +1,478 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Vidalee/kacao/serde"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// NullMarker is displayed in place of a null key or value, so that it can be told apart from an empty one
+const NullMarker = "<null>"
+
+const (
+	EncodingText   = "text"
+	EncodingHex    = "hex"
+	EncodingBase64 = "base64"
+	// EncodingAuto renders printable data as text and anything else as base64
+	EncodingAuto = "auto"
+)
+
+var Encodings = []string{EncodingText, EncodingHex, EncodingBase64, EncodingAuto}
+
+// RecordEncodings are the encodings used to render the keys, values and header values of records, and the optional
+// deserializers applied to keys and values first
+type RecordEncodings struct {
+	Key               string
+	Value             string
+	Header            string
+	KeyDeserializer   Deserializer
+	ValueDeserializer Deserializer
+	// TopicDeserializers are the deserializers of topics from the configuration of the cluster, used when
+	// KeyDeserializer or ValueDeserializer is not set
+	TopicDeserializers map[string]TopicDeserializers
+}
+
+func AddEncodingFlags(command *cobra.Command) {
+	usage := "Encoding used to display %s: text, hex, base64 or auto (text if printable, base64 otherwise)"
+	command.Flags().String("key-encoding", EncodingText, fmt.Sprintf(usage, "keys"))
+	command.Flags().String("value-encoding", EncodingText, fmt.Sprintf(usage, "values"))
+	command.Flags().String("header-encoding", EncodingText, fmt.Sprintf(usage, "header values"))
+	decoderUsage := "Decoder of %s, overriding the decoders of the topic in the cluster configuration: " + DeserializersUsage
+	command.Flags().String("key-decoder", DeserializerNone, fmt.Sprintf(decoderUsage, "keys"))
+	command.Flags().String("value-decoder", DeserializerNone, fmt.Sprintf(decoderUsage, "values"))
+	command.Flags().String("proto-descriptor-set", "", "Protobuf descriptor set, as written by 'protoc --include_imports --descriptor_set_out', used to decode values with --proto-message")
+	command.Flags().String("proto-message", "", "Full name of the Protobuf message of the values, example: shop.Order")
+	command.MarkFlagsRequiredTogether("proto-descriptor-set", "proto-message")
+	command.MarkFlagsMutuallyExclusive("value-decoder", "proto-message")
+}
+
+func GetEncodingFlags(command *cobra.Command) (RecordEncodings, error) {
+	var encodings RecordEncodings
+	flags := []struct {
+		name     string
+		encoding *string
+	}{
+		{"key-encoding", &encodings.Key},
+		{"value-encoding", &encodings.Value},
+		{"header-encoding", &encodings.Header},
+	}
+	for _, flag := range flags {
+		value, err := command.Flags().GetString(flag.name)
+		if err != nil {
+			return encodings, err
+		}
+		if !slices.Contains(Encodings, value) {
+			return encodings, fmt.Errorf("invalid --%s '%s'. Use one of: %s", flag.name, value, strings.Join(Encodings, ", "))
+		}
+		*flag.encoding = value
+	}
+
+	keySpec, err := command.Flags().GetString("key-decoder")
+	if err != nil {
+		return encodings, err
+	}
+	valueSpec, err := command.Flags().GetString("value-decoder")
+	if err != nil {
+		return encodings, err
+	}
+	descriptorSet, err := command.Flags().GetString("proto-descriptor-set")
+	if err != nil {
+		return encodings, err
+	}
+	protoMessage, err := command.Flags().GetString("proto-message")
+	if err != nil {
+		return encodings, err
+	}
+	// Flags are validated before the configuration is read, so that their errors are reported first
+	decoderFlags := []struct {
+		name string
+		spec string
+	}{
+		{"key-decoder", keySpec},
+		{"value-decoder", valueSpec},
+	}
+	for _, flag := range decoderFlags {
+		if _, err := parseDeserializerSpec(flag.spec); err != nil {
+			return encodings, fmt.Errorf("invalid --%s '%s': %v", flag.name, flag.spec, err)
+		}
+	}
+	valueFlagSet := command.Flags().Changed("value-decoder")
+	if protoMessage != "" {
+		valueSpec = DeserializerProtobuf + ":" + descriptorSet + ":" + protoMessage
+		valueFlagSet = true
+	}
+
+	factory := newDeserializerFactory()
+	encodings.KeyDeserializer, err = factory.New(keySpec)
+	if err != nil {
+		return encodings, err
+	}
+	encodings.ValueDeserializer, err = factory.New(valueSpec)
+	if err != nil {
+		return encodings, err
+	}
+	keyFlagSet := command.Flags().Changed("key-decoder")
+	if keyFlagSet && valueFlagSet {
+		return encodings, nil
+	}
+	encodings.TopicDeserializers, err = factory.topicDeserializers()
+	if err != nil {
+		return encodings, err
+	}
+	// Decoders set by flags apply to all topics, even to none
+	for topic, deserializers := range encodings.TopicDeserializers {
+		if keyFlagSet {
+			deserializers.Key = nil
+		}
+		if valueFlagSet {
+			deserializers.Value = nil
+		}
+		encodings.TopicDeserializers[topic] = deserializers
+	}
+	return encodings, nil
+}
+
+// keyDeserializer returns the deserializer of the keys of topic, if any
+func (e RecordEncodings) keyDeserializer(topic string) Deserializer {
+	if e.KeyDeserializer != nil {
+		return e.KeyDeserializer
+	}
+	return e.TopicDeserializers[topic].Key
+}
+
+// valueDeserializer returns the deserializer of the values of topic, if any
+func (e RecordEncodings) valueDeserializer(topic string) Deserializer {
+	if e.ValueDeserializer != nil {
+		return e.ValueDeserializer
+	}
+	return e.TopicDeserializers[topic].Value
+}
+
+// EncodeBytes renders data with encoding, and returns the encoding that was used, auto being resolved to text or base64
+func EncodeBytes(data []byte, encoding string) (string, string) {
+	if encoding == EncodingAuto {
+		encoding = EncodingBase64
+		if isPrintable(data) {
+			encoding = EncodingText
+		}
+	}
+	switch encoding {
+	case EncodingHex:
+		return hex.EncodeToString(data), encoding
+	case EncodingBase64:
+		return base64.StdEncoding.EncodeToString(data), encoding
+	default:
+		return string(data), EncodingText
+	}
+}
+
+// losslessEncoding replaces the text encoding by auto, as text cannot hold bytes which are not valid UTF-8
+func losslessEncoding(encoding string) string {
+	if encoding == EncodingText {
+		return EncodingAuto
+	}
+	return encoding
+}
+
+func DecodeString(text string, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", EncodingText:
+		return []byte(text), nil
+	case EncodingHex:
+		return hex.DecodeString(text)
+	case EncodingBase64:
+		return base64.StdEncoding.DecodeString(text)
+	}
+	return nil, fmt.Errorf("unknown encoding '%s'", encoding)
+}
+
+func isPrintable(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func FormatRecordBytes(data []byte, encoding string) string {
+	if data == nil {
+		return NullMarker
+	}
+	text, _ := EncodeBytes(data, encoding)
+	return text
+}
+
+// FormatKey renders the key of record, which is left empty for control records
+func (e RecordEncodings) FormatKey(record *kgo.Record) (string, error) {
+	if record.Attrs.IsControl() {
+		return "", nil
+	}
+	key, _, err := decodeNullableString(record.Key, e.Key, e.keyDeserializer(record.Topic))
+	return key.String(), err
+}
+
+// FormatValue renders the value of record, or a description of the marker for control records
+func (e RecordEncodings) FormatValue(record *kgo.Record) (string, error) {
+	if record.Attrs.IsControl() {
+		return fmt.Sprintf("<%s marker, producer id %d>", ControlRecordType(record), record.ProducerID), nil
+	}
+	value, _, err := decodeNullableString(record.Value, e.Value, e.valueDeserializer(record.Topic))
+	return value.String(), err
+}
+
+// NullableString is a record key, value or header value which keeps track of Kafka nulls.
+// It is printed as NullMarker in templates and as null in JSON.
+type NullableString struct {
+	Value string
+	Null  bool
+}
+
+// NewNullableString renders data with encoding, and returns the encoding that was used, empty for text
+func NewNullableString(data []byte, encoding string) (NullableString, string) {
+	text, encoding := EncodeBytes(data, encoding)
+	if encoding == EncodingText {
+		encoding = ""
+	}
+	return NullableString{Value: text, Null: data == nil}, encoding
+}
+
+// decodeNullableString renders data with deserializer if it handles it, and with encoding otherwise.
+// Deserialized data is text, so the returned encoding is empty.
+func decodeNullableString(data []byte, encoding string, deserializer Deserializer) (NullableString, string, error) {
+	if data != nil && deserializer != nil {
+		text, err := deserializer.Deserialize(data)
+		if err == nil {
+			return NullableString{Value: text}, "", nil
+		}
+		if !errors.Is(err, serde.ErrNotWireFormat) {
+			return NullableString{}, "", err
+		}
+	}
+	value, encoding := NewNullableString(data, encoding)
+	return value, encoding, nil
+}
+
+func (s NullableString) String() string {
+	if s.Null {
+		return NullMarker
+	}
+	return s.Value
+}
+
+func (s NullableString) Bytes(encoding string) ([]byte, error) {
+	if s.Null {
+		return nil, nil
+	}
+	return DecodeString(s.Value, encoding)
+}
+
+func (s NullableString) MarshalJSON() ([]byte, error) {
+	if s.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(s.Value)
+}
+
+func (s *NullableString) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*s = NullableString{Null: true}
+		return nil
+	}
+	s.Null = false
+	return json.Unmarshal(data, &s.Value)
+}
+
+type HeaderView struct {
+	Key      string         `json:"key"`
+	Value    NullableString `json:"value"`
+	Encoding string         `json:"encoding,omitempty"`
+}
+
+// RecordView is the representation of a record used by the output formats of consume,
+// and read back by produce --from-file. Encodings are left empty for text.
+type RecordView struct {
+	Topic         string         `json:"topic"`
+	Partition     int32          `json:"partition"`
+	Offset        int64          `json:"offset"`
+	Timestamp     time.Time      `json:"timestamp"`
+	Key           NullableString `json:"key"`
+	KeyEncoding   string         `json:"key_encoding,omitempty"`
+	Value         NullableString `json:"value"`
+	ValueEncoding string         `json:"value_encoding,omitempty"`
+	Headers       []HeaderView   `json:"headers"`
+	// ProducerID is only set for records written in a transaction, and Control for the markers ending them
+	ProducerID int64  `json:"producer_id,omitempty"`
+	Control    string `json:"control,omitempty"`
+}
+
+func NewRecordView(record *kgo.Record, encodings RecordEncodings) (RecordView, error) {
+	headers := make([]HeaderView, 0, len(record.Headers))
+	for _, header := range record.Headers {
+		value, encoding := NewNullableString(header.Value, encodings.Header)
+		headers = append(headers, HeaderView{Key: header.Key, Value: value, Encoding: encoding})
+	}
+	keyDeserializer, valueDeserializer := encodings.keyDeserializer(record.Topic), encodings.valueDeserializer(record.Topic)
+	if record.Attrs.IsControl() {
+		keyDeserializer, valueDeserializer = nil, nil
+	}
+	key, keyEncoding, err := decodeNullableString(record.Key, encodings.Key, keyDeserializer)
+	if err != nil {
+		return RecordView{}, fmt.Errorf("error decoding key: %v", err)
+	}
+	value, valueEncoding, err := decodeNullableString(record.Value, encodings.Value, valueDeserializer)
+	if err != nil {
+		return RecordView{}, fmt.Errorf("error decoding value: %v", err)
+	}
+	view := RecordView{
+		Topic:         record.Topic,
+		Partition:     record.Partition,
+		Offset:        record.Offset,
+		Timestamp:     record.Timestamp,
+		Key:           key,
+		KeyEncoding:   keyEncoding,
+		Value:         value,
+		ValueEncoding: valueEncoding,
+		Headers:       headers,
+	}
+	if record.Attrs.IsTransactional() {
+		view.ProducerID = record.ProducerID
+	}
+	if record.Attrs.IsControl() {
+		view.Control = ControlRecordType(record)
+	}
+	return view, nil
+}
+
+// ToRecord builds a record to produce to topic. Partition and offset are left to the producer.
+func (v RecordView) ToRecord(topic string) (*kgo.Record, error) {
+	headers := make([]kgo.RecordHeader, 0, len(v.Headers))
+	for _, header := range v.Headers {
+		value, err := header.Value.Bytes(header.Encoding)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for header '%s': %v", header.Key, err)
+		}
+		headers = append(headers, kgo.RecordHeader{Key: header.Key, Value: value})
+	}
+	key, err := v.Key.Bytes(v.KeyEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %v", err)
+	}
+	value, err := v.Value.Bytes(v.ValueEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value: %v", err)
+	}
+	return &kgo.Record{
+		Topic:     topic,
+		Timestamp: v.Timestamp,
+		Key:       key,
+		Value:     value,
+		Headers:   headers,
+	}, nil
+}
+
+// ReadRecordViews reads one JSON record per line, as written by the jsonl output format. Empty lines are skipped.
+func ReadRecordViews(reader io.Reader) ([]RecordView, error) {
+	var views []RecordView
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var view RecordView
+		if err := json.Unmarshal(scanner.Bytes(), &view); err != nil {
+			return nil, fmt.Errorf("invalid record on line %d: %v", line, err)
+		}
+		views = append(views, view)
+	}
+	return views, scanner.Err()
+}
+
+// RecordFormatter writes records in one of the output formats of consume
+type RecordFormatter func(out io.Writer, record *kgo.Record) error
+
+// NewRecordFormatter returns the formatter for format, which is either empty (value only), json, jsonl, or a Go template
+// executed against a RecordView.
+func NewRecordFormatter(format string, encodings RecordEncodings) (RecordFormatter, error) {
+	switch format {
+	case "":
+		return func(out io.Writer, record *kgo.Record) error {
+			value, err := encodings.FormatValue(record)
+			if err != nil {
+				return fmt.Errorf("error decoding value: %v", err)
+			}
+			_, err = fmt.Fprintln(out, value)
+			return err
+		}, nil
+	case "json":
+		return func(out io.Writer, record *kgo.Record) error {
+			view, err := NewRecordView(record, encodings)
+			if err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(view, "", "  ")
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(out, string(data))
+			return err
+		}, nil
+	case "jsonl":
+		// The jsonl output is read back by produce --from-file, so it keeps the raw keys and values, without decoders,
+		// and renders anything that is not printable as base64 rather than as text
+		rawEncodings := RecordEncodings{
+			Key:    losslessEncoding(encodings.Key),
+			Value:  losslessEncoding(encodings.Value),
+			Header: losslessEncoding(encodings.Header),
+		}
+		return func(out io.Writer, record *kgo.Record) error {
+			view, err := NewRecordView(record, rawEncodings)
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(view)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(out, string(data))
+			return err
+		}, nil
+	}
+
+	tmpl, err := template.New("format").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format template: %v", err)
+	}
+	return func(out io.Writer, record *kgo.Record) error {
+		view, err := NewRecordView(record, encodings)
+		if err != nil {
+			return err
+		}
+		if err := tmpl.Execute(out, view); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out)
+		return err
+	}, nil
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vidalee/kacao/serde"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var textEncodings = RecordEncodings{Key: EncodingText, Value: EncodingText, Header: EncodingText}
+
+// prefixDeserializer decodes data starting with "encoded:" to a JSON string, and fails on data starting with "invalid:"
+type prefixDeserializer struct{}
+
+func (prefixDeserializer) Deserialize(data []byte) (string, error) {
+	if text, ok := bytes.CutPrefix(data, []byte("encoded:")); ok {
+		return fmt.Sprintf("%q", text), nil
+	}
+	if bytes.HasPrefix(data, []byte("invalid:")) {
+		return "", errors.New("unknown schema")
+	}
+	return "", serde.ErrNotWireFormat
+}
+
+func TestRecordFormatter(t *testing.T) {
+	record := &kgo.Record{
+		Topic:     "topic",
+		Partition: 1,
+		Offset:    42,
+		Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Key:       nil,
+		Value:     []byte("value"),
+		Headers:   []kgo.RecordHeader{{Key: "header-key", Value: []byte("header-value")}},
+	}
+
+	tests := []struct {
+		name           string
+		format         string
+		encodings      RecordEncodings
+		record         *kgo.Record
+		expectedOutput string
+		expectedError  bool
+	}{
+		{
+			name:           "default format prints the value",
+			format:         "",
+			record:         record,
+			expectedOutput: "value\n",
+		},
+		{
+			name:           "default format prints null values",
+			format:         "",
+			record:         &kgo.Record{Value: nil},
+			expectedOutput: "<null>\n",
+		},
+		{
+			name:           "jsonl format",
+			format:         "jsonl",
+			record:         record,
+			expectedOutput: `{"topic":"topic","partition":1,"offset":42,"timestamp":"2025-01-01T00:00:00Z","key":null,"value":"value","headers":[{"key":"header-key","value":"header-value"}]}` + "\n",
+		},
+		{
+			name:   "json format",
+			format: "json",
+			record: &kgo.Record{Topic: "topic", Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Key: []byte(""), Value: []byte("value")},
+			expectedOutput: `{
+  "topic": "topic",
+  "partition": 0,
+  "offset": 0,
+  "timestamp": "2025-01-01T00:00:00Z",
+  "key": "",
+  "value": "value",
+  "headers": []
+}
+`,
+		},
+		{
+			name:           "template format",
+			format:         "{{.Partition}}:{{.Offset}} {{.Key}} => {{.Value}}{{range .Headers}} {{.Key}}={{.Value}}{{end}}",
+			record:         record,
+			expectedOutput: "1:42 <null> => value header-key=header-value\n",
+		},
+		{
+			name:           "jsonl format with binary encodings",
+			format:         "jsonl",
+			encodings:      RecordEncodings{Key: EncodingHex, Value: EncodingAuto, Header: EncodingAuto},
+			record:         &kgo.Record{Topic: "topic", Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Key: []byte{0xca, 0xfe}, Value: []byte{0x00, 0xff}, Headers: []kgo.RecordHeader{{Key: "text", Value: []byte("printable")}}},
+			expectedOutput: `{"topic":"topic","partition":0,"offset":0,"timestamp":"2025-01-01T00:00:00Z","key":"cafe","key_encoding":"hex","value":"AP8=","value_encoding":"base64","headers":[{"key":"text","value":"printable"}]}` + "\n",
+		},
+		{
+			name:           "jsonl format with text encoding and binary data",
+			format:         "jsonl",
+			record:         &kgo.Record{Topic: "topic", Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Key: []byte("key"), Value: []byte{0xff, 0x00}, Headers: []kgo.RecordHeader{{Key: "binary", Value: []byte{0x80}}}},
+			expectedOutput: `{"topic":"topic","partition":0,"offset":0,"timestamp":"2025-01-01T00:00:00Z","key":"key","value":"/wA=","value_encoding":"base64","headers":[{"key":"binary","value":"gA==","encoding":"base64"}]}` + "\n",
+		},
+		{
+			name:           "template format with base64 encoding",
+			format:         "{{.Key}} => {{.Value}}",
+			encodings:      RecordEncodings{Key: EncodingText, Value: EncodingBase64, Header: EncodingText},
+			record:         &kgo.Record{Key: nil, Value: []byte("value")},
+			expectedOutput: "<null> => dmFsdWU=\n",
+		},
+		{
+			name:           "jsonl format ignores decoders",
+			format:         "jsonl",
+			encodings:      RecordEncodings{Key: EncodingHex, Value: EncodingText, Header: EncodingText, KeyDeserializer: prefixDeserializer{}, ValueDeserializer: prefixDeserializer{}},
+			record:         &kgo.Record{Topic: "topic", Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Key: []byte("raw"), Value: []byte("encoded:value")},
+			expectedOutput: `{"topic":"topic","partition":0,"offset":0,"timestamp":"2025-01-01T00:00:00Z","key":"726177","key_encoding":"hex","value":"encoded:value","headers":[]}` + "\n",
+		},
+		{
+			name:           "default format with decoder and null value",
+			format:         "",
+			encodings:      RecordEncodings{Key: EncodingText, Value: EncodingText, Header: EncodingText, ValueDeserializer: prefixDeserializer{}},
+			record:         &kgo.Record{Value: nil},
+			expectedOutput: "<null>\n",
+		},
+		{
+			name:           "decoder of the topic",
+			format:         "{{.Topic}} {{.Value}}",
+			encodings:      RecordEncodings{Key: EncodingText, Value: EncodingText, Header: EncodingText, TopicDeserializers: map[string]TopicDeserializers{"orders": {Value: prefixDeserializer{}}}},
+			record:         &kgo.Record{Topic: "orders", Value: []byte("encoded:value")},
+			expectedOutput: "orders \"value\"\n",
+		},
+		{
+			name:           "key decoder of the topic",
+			format:         "{{.Key}} {{.Value}}",
+			encodings:      RecordEncodings{Key: EncodingText, Value: EncodingText, Header: EncodingText, TopicDeserializers: map[string]TopicDeserializers{"orders": {Key: prefixDeserializer{}}}},
+			record:         &kgo.Record{Topic: "orders", Key: []byte("encoded:key"), Value: []byte("encoded:value")},
+			expectedOutput: "\"key\" encoded:value\n",
+		},
+		{
+			name:           "no decoder for other topics",
+			format:         "{{.Topic}} {{.Value}}",
+			encodings:      RecordEncodings{Key: EncodingText, Value: EncodingText, Header: EncodingText, TopicDeserializers: map[string]TopicDeserializers{"orders": {Value: prefixDeserializer{}}}},
+			record:         &kgo.Record{Topic: "payments", Value: []byte("encoded:value")},
+			expectedOutput: "payments encoded:value\n",
+		},
+		{
+			name:           "json decoder with a value which is not JSON",
+			format:         "{{.Key}} {{.Value}}",
+			encodings:      RecordEncodings{Key: EncodingText, Value: EncodingHex, Header: EncodingText, KeyDeserializer: serde.JSONDeserializer{}, ValueDeserializer: serde.JSONDeserializer{}},
+			record:         &kgo.Record{Key: []byte(`{ "id": 1 }`), Value: []byte("not json")},
+			expectedOutput: "{\"id\":1} 6e6f74206a736f6e\n",
+		},
+		{
+			name:          "decoding error",
+			format:        "",
+			encodings:     RecordEncodings{Key: EncodingText, Value: EncodingText, Header: EncodingText, ValueDeserializer: prefixDeserializer{}},
+			record:        &kgo.Record{Value: []byte("invalid:value")},
+			expectedError: true,
+		},
+		{
+			name:          "invalid template",
+			format:        "{{.Value",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encodings := tt.encodings
+			if encodings.Key == "" {
+				encodings = textEncodings
+			}
+			formatRecord, err := NewRecordFormatter(tt.format, encodings)
+			if err == nil && tt.record != nil {
+				err = formatRecord(&bytes.Buffer{}, tt.record)
+			}
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var buf bytes.Buffer
+			err = formatRecord(&buf, tt.record)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOutput, buf.String())
+		})
+	}
+}
+
+func TestReadRecordViewsRoundTrip(t *testing.T) {
+	records := []*kgo.Record{
+		{Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Key: []byte("key"), Value: []byte("value"), Headers: []kgo.RecordHeader{{Key: "a", Value: nil}}},
+		{Timestamp: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Key: nil, Value: nil, Headers: []kgo.RecordHeader{}},
+		{Timestamp: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), Key: []byte(""), Value: []byte(""), Headers: []kgo.RecordHeader{}},
+		{Timestamp: time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC), Key: []byte{0x00, 0x01}, Value: []byte{0xff, 0xfe, 0x0a}, Headers: []kgo.RecordHeader{{Key: "b", Value: []byte{0x80}}}},
+	}
+
+	formatRecord, err := NewRecordFormatter("jsonl", RecordEncodings{Key: EncodingAuto, Value: EncodingAuto, Header: EncodingHex})
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	for _, record := range records {
+		assert.NoError(t, formatRecord(&buf, record))
+	}
+
+	views, err := ReadRecordViews(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, views, len(records))
+	for i, view := range views {
+		produced, err := view.ToRecord("destination")
+		assert.NoError(t, err)
+		assert.Equal(t, "destination", produced.Topic)
+		assert.True(t, records[i].Timestamp.Equal(produced.Timestamp))
+		assert.Equal(t, records[i].Key, produced.Key)
+		assert.Equal(t, records[i].Value, produced.Value)
+		assert.Equal(t, records[i].Headers, produced.Headers)
+	}
+
+	_, err = ReadRecordViews(strings.NewReader("{}\n\nnot json\n"))
+	assert.EqualError(t, err, "invalid record on line 3: invalid character 'o' in literal null (expecting 'u')")
+
+	views, err = ReadRecordViews(strings.NewReader(`{"value":"zz","value_encoding":"hex"}`))
+	assert.NoError(t, err)
+	_, err = views[0].ToRecord("destination")
+	assert.EqualError(t, err, "invalid value: encoding/hex: invalid byte: U+007A 'z'")
+}
+
+func TestEncodeBytes(t *testing.T) {
+	tests := []struct {
+		name             string
+		data             []byte
+		encoding         string
+		expectedText     string
+		expectedEncoding string
+	}{
+		{"text", []byte("hello"), EncodingText, "hello", EncodingText},
+		{"hex", []byte("hello"), EncodingHex, "68656c6c6f", EncodingHex},
+		{"base64", []byte("hello"), EncodingBase64, "aGVsbG8=", EncodingBase64},
+		{"auto printable", []byte("héllo\tworld\n"), EncodingAuto, "héllo\tworld\n", EncodingText},
+		{"auto control character", []byte("hello\x1b[31m"), EncodingAuto, "aGVsbG8bWzMxbQ==", EncodingBase64},
+		{"auto invalid utf8", []byte{0xff, 0x00}, EncodingAuto, "/wA=", EncodingBase64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, encoding := EncodeBytes(tt.data, tt.encoding)
+			assert.Equal(t, tt.expectedText, text)
+			assert.Equal(t, tt.expectedEncoding, encoding)
+
+			decoded, err := DecodeString(text, encoding)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.data, decoded)
+		})
+	}
+
+	assert.Equal(t, NullMarker, FormatRecordBytes(nil, EncodingHex))
+}
+
+func TestGetEncodingFlagsInvalidDecoders(t *testing.T) {
+	command := &cobra.Command{}
+	AddEncodingFlags(command)
+	assert.NoError(t, command.ParseFlags([]string{"--key-decoder", "thrift", "--value-decoder", "avro"}))
+
+	// The key decoder is always reported first
+	for range 10 {
+		_, err := GetEncodingFlags(command)
+		assert.EqualError(t, err, "invalid --key-decoder 'thrift': unknown decoder. Use one of: none, text, json, schema-registry, avro:<schema.avsc>, protobuf:<descriptor_set.pb>:<message>, exec:<command>")
+	}
+}
@@ -6,14 +6,33 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/Vidalee/kacao/serde"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/twmb/franz-go/pkg/sr"
 )
 
 var DefaultConsumerGroup = "kacao-cli"
 
 var cfgFile string
 
+// ExitCodeError makes kacao exit with Code instead of 1
+type ExitCodeError struct {
+	Err  error
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error {
+	return e.Err
+}
+
+// ErrInterrupted is returned by commands stopped by SIGINT or SIGTERM, and exits with the usual 130 code
+var ErrInterrupted = &ExitCodeError{Err: errors.New("interrupted"), Code: 130}
+
 var RootCmd = &cobra.Command{
 	Use:   "kacao",
 	Short: "Kafka CLI",
@@ -23,6 +42,10 @@ var RootCmd = &cobra.Command{
 func Execute() {
 	err := RootCmd.Execute()
 	if err != nil {
+		var exitCodeError *ExitCodeError
+		if errors.As(err, &exitCodeError) {
+			os.Exit(exitCodeError.Code)
+		}
 		os.Exit(1)
 	}
 }
@@ -67,10 +90,10 @@ func initConfig() {
 	}
 }
 
-func GetCurrentClusterBootstrapServers() ([]string, error) {
+func GetCurrentClusterName() (string, error) {
 	contexts := viper.GetStringMap("contexts")
 	if len(contexts) == 0 {
-		return []string{}, errors.New("no contexts set. Use 'kacao config set-context NAME' to set a context")
+		return "", errors.New("no contexts set. Use 'kacao config set-context NAME' to set a context")
 	}
 
 	if len(contexts) == 1 {
@@ -82,12 +105,20 @@ func GetCurrentClusterBootstrapServers() ([]string, error) {
 
 	currentContext := viper.GetString("current-context")
 	if currentContext == "" {
-		return []string{}, errors.New("no context set. Use 'kacao config use-context NAME' to set a context")
+		return "", errors.New("no context set. Use 'kacao config use-context NAME' to set a context")
 	}
 
 	clusterName := viper.GetString("contexts." + currentContext + ".cluster")
 	if clusterName == "" {
-		return []string{}, fmt.Errorf("context '%s' has no cluster set", currentContext)
+		return "", fmt.Errorf("context '%s' has no cluster set", currentContext)
+	}
+	return clusterName, nil
+}
+
+func GetCurrentClusterBootstrapServers() ([]string, error) {
+	clusterName, err := GetCurrentClusterName()
+	if err != nil {
+		return []string{}, err
 	}
 
 	bootstrapServers := viper.GetStringSlice("clusters." + clusterName + ".bootstrap-servers")
@@ -97,6 +128,52 @@ func GetCurrentClusterBootstrapServers() ([]string, error) {
 	return bootstrapServers, nil
 }
 
+func GetCurrentClusterSchemaRegistry() (serde.RegistryConfig, error) {
+	clusterName, err := GetCurrentClusterName()
+	if err != nil {
+		return serde.RegistryConfig{}, err
+	}
+
+	config := serde.RegistryConfig{
+		URL:      viper.GetString("clusters." + clusterName + ".schema-registry"),
+		Username: viper.GetString("clusters." + clusterName + ".schema-registry-username"),
+		Password: viper.GetString("clusters." + clusterName + ".schema-registry-password"),
+	}
+	if config.URL == "" {
+		return serde.RegistryConfig{}, fmt.Errorf("no schema registry set for cluster '%s'. Use 'kacao config set-cluster %s --schema-registry URL' to set one", clusterName, clusterName)
+	}
+	return config, nil
+}
+
+// NewSchemaRegistryClient returns a client of the schema registry of the current cluster
+func NewSchemaRegistryClient() (*sr.Client, error) {
+	registryConfig, err := GetCurrentClusterSchemaRegistry()
+	if err != nil {
+		return nil, err
+	}
+	client, err := serde.NewRegistryClient(registryConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema registry: %v", err)
+	}
+	return client, nil
+}
+
+// TopicDecoders are the decoders of the keys and values of a topic, from the configuration of its cluster. Decoders are
+// written like --key-decoder and --value-decoder, an empty one leaving the field to the flags.
+type TopicDecoders struct {
+	Topic string `mapstructure:"topic"`
+	Key   string `mapstructure:"key"`
+	Value string `mapstructure:"value"`
+}
+
+func GetClusterTopicDecoders(clusterName string) ([]TopicDecoders, error) {
+	var topicDecoders []TopicDecoders
+	if err := viper.UnmarshalKey("clusters."+clusterName+".topic-decoders", &topicDecoders); err != nil {
+		return nil, fmt.Errorf("invalid topic-decoders of cluster '%s': %v", clusterName, err)
+	}
+	return topicDecoders, nil
+}
+
 func GetConsumerGroup() (string, error) {
 	contexts := viper.GetStringMap("contexts")
 	if len(contexts) == 0 {
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+// FormatSchema returns the text of a schema, indented for Avro and JSON Schema
+func FormatSchema(schema sr.Schema) string {
+	if schema.Type == sr.TypeProtobuf {
+		return schema.Schema
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(schema.Schema), "", "  "); err != nil {
+		return schema.Schema
+	}
+	return indented.String()
+}
+
+// FormatReferences returns the references of a schema as name=subject:version, separated by commas
+func FormatReferences(references []sr.SchemaReference) string {
+	if len(references) == 0 {
+		return "-"
+	}
+	formatted := make([]string, 0, len(references))
+	for _, reference := range references {
+		formatted = append(formatted, fmt.Sprintf("%s=%s:%d", reference.Name, reference.Subject, reference.Version))
+	}
+	return strings.Join(formatted, ",")
+}
+
+// ParseReference parses a schema reference written as name=subject:version
+func ParseReference(reference string) (sr.SchemaReference, error) {
+	name, subjectVersion, ok := strings.Cut(reference, "=")
+	subject, versionArg, ok2 := strings.Cut(subjectVersion, ":")
+	version, err := strconv.Atoi(versionArg)
+	if !ok || !ok2 || name == "" || subject == "" || err != nil {
+		return sr.SchemaReference{}, fmt.Errorf("invalid reference '%s'. Expected name=subject:version", reference)
+	}
+	return sr.SchemaReference{Name: name, Subject: subject, Version: version}, nil
+}
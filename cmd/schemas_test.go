@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+func TestFormatSchema(t *testing.T) {
+	assert.Equal(t, "{\n  \"type\": \"string\"\n}", FormatSchema(sr.Schema{Schema: `{"type":"string"}`}))
+	assert.Equal(t, "{\n  \"type\": \"object\"\n}", FormatSchema(sr.Schema{Schema: `{"type":"object"}`, Type: sr.TypeJSON}))
+	assert.Equal(t, `syntax = "proto3"; message A {}`, FormatSchema(sr.Schema{Schema: `syntax = "proto3"; message A {}`, Type: sr.TypeProtobuf}))
+	assert.Equal(t, `"string"`, FormatSchema(sr.Schema{Schema: `"string"`}))
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		reference     string
+		expected      sr.SchemaReference
+		expectedError bool
+	}{
+		{reference: "com.example.Item=item-value:1", expected: sr.SchemaReference{Name: "com.example.Item", Subject: "item-value", Version: 1}},
+		{reference: "customer.proto=customer:-1", expected: sr.SchemaReference{Name: "customer.proto", Subject: "customer", Version: -1}},
+		{reference: "item-value:1", expectedError: true},
+		{reference: "com.example.Item=item-value", expectedError: true},
+		{reference: "com.example.Item=item-value:latest", expectedError: true},
+		{reference: "=item-value:1", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reference, func(t *testing.T) {
+			reference, err := ParseReference(tt.reference)
+			if tt.expectedError {
+				assert.ErrorContains(t, err, "Expected name=subject:version")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, reference)
+			assert.Equal(t, tt.reference, FormatReferences([]sr.SchemaReference{reference}))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var brokerCmd = &cobra.Command{
+	Use:   "broker <broker_id|--all> [key=value ...] [--delete-key <key>] [--append <key=value>] [--subtract <key=value>]",
+	Short: "Alter the dynamic configuration of a broker, or of all brokers",
+	Long: `Alter the dynamic configuration of a broker, or the cluster-wide default of all brokers with --all
+
+Configs are altered incrementally: configs which are not specified keep their value. Only dynamic configs can be
+altered, static configs of server.properties being read only.
+- kacao set config broker 1 log.cleaner.threads=2
+- kacao set config broker --all leader.replication.throttled.rate=10485760
+- kacao set config broker 1 --delete-key log.cleaner.threads
+
+Configs deleted from a broker revert to the cluster-wide default, then to the static config. The values of the altered
+configs are printed before and after the change. Use 'kacao describe broker <broker_id>' to display all configs with
+their source.`,
+	RunE: func(command *cobra.Command, args []string) error {
+		all, err := command.Flags().GetBool("all")
+		cobra.CheckErr(err)
+
+		var brokerIDs []int32
+		resource, resourceName := "the cluster-wide default of brokers", ""
+		if !all {
+			if len(args) == 0 {
+				return errors.New("a broker id or --all is required")
+			}
+			brokerID, err := strconv.ParseInt(args[0], 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid broker id '%s'", args[0])
+			}
+			brokerIDs = append(brokerIDs, int32(brokerID))
+			resource, resourceName = fmt.Sprintf("broker %d", brokerID), args[0]
+			args = args[1:]
+		}
+		configs, err := getAlterConfigs(command, args)
+		if err != nil {
+			return err
+		}
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		ctx := context.Background()
+
+		resourceConfigs, err := adminClient.DescribeBrokerConfigs(ctx, brokerIDs...)
+		before, err := resourceConfigOn(resourceConfigs, err, resourceName, resource)
+		if err != nil {
+			return err
+		}
+		responses, err := adminClient.AlterBrokerConfigs(ctx, configs, brokerIDs...)
+		if err == nil {
+			err = cmd.AlterConfigsError(responses)
+		}
+		if err != nil {
+			return fmt.Errorf("error altering config of %s: %v", resource, err)
+		}
+		resourceConfigs, err = adminClient.DescribeBrokerConfigs(ctx, brokerIDs...)
+		after, err := resourceConfigOn(resourceConfigs, err, resourceName, resource)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "Altered config of %s\n", resource)
+		cobra.CheckErr(err)
+		printConfigDiff(command.OutOrStdout(), configs, before, after)
+		return nil
+	},
+}
+
+func init() {
+	brokerCmd.Flags().Bool("all", false, "Alter the cluster-wide default config of all brokers")
+	addAlterConfigFlags(brokerCmd)
+
+	configCmd.AddCommand(brokerCmd)
+}
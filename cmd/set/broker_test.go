@@ -0,0 +1,147 @@
+package set
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestSetBrokerConfig(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	// Cases run in order, on the same broker
+	tests := []struct {
+		name             string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+		// Brokers to describe after the command, none for the cluster-wide default
+		describeBrokers []int32
+		expectedConfigs map[string]string
+	}{
+		{
+			name: "set config of a broker",
+			args: []string{"set", "config", "broker", "1", "log.cleaner.threads=2"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Altered config of broker 1\nConfig\s+Before\s+After\s+\nlog.cleaner.threads\s+1\s+2\s+\n$`),
+			},
+			describeBrokers: []int32{1},
+			expectedConfigs: map[string]string{"log.cleaner.threads": "2"},
+		},
+		{
+			name: "delete config of a broker",
+			args: []string{"set", "config", "broker", "1", "--delete-key", "log.cleaner.threads"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`log.cleaner.threads\s+2\s+1\s+\n$`),
+			},
+			describeBrokers: []int32{1},
+			expectedConfigs: map[string]string{"log.cleaner.threads": "1"},
+		},
+		{
+			name: "set cluster-wide default config",
+			args: []string{"set", "config", "broker", "--all", "leader.replication.throttled.rate=10485760"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Altered config of the cluster-wide default of brokers\nConfig\s+Before\s+After\s+\nleader.replication.throttled.rate\s+-\s+10485760\s+\n$`),
+			},
+			expectedConfigs: map[string]string{"leader.replication.throttled.rate": "10485760"},
+		},
+		{
+			name:          "alter static config",
+			args:          []string{"set", "config", "broker", "1", "log.dirs=/tmp"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: error altering config of broker 1: INVALID_REQUEST`),
+			},
+		},
+		{
+			name:          "no broker",
+			args:          []string{"set", "config", "broker"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: a broker id or --all is required`),
+			},
+		},
+		{
+			name:          "invalid broker id",
+			args:          []string{"set", "config", "broker", "log.cleaner.threads=2"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid broker id 'log.cleaner.threads=2'`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+
+			if tt.expectedConfigs == nil {
+				return
+			}
+			resourceConfigs, err := adminClient.DescribeBrokerConfigs(ctx, tt.describeBrokers...)
+			assert.NoError(t, err)
+			for _, resourceConfig := range resourceConfigs {
+				for _, config := range resourceConfig.Configs {
+					if expectedValue, ok := tt.expectedConfigs[config.Key]; ok {
+						assert.Equal(t, expectedValue, *config.Value)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+// addAlterConfigFlags adds the flags of the incremental operations other than setting a value, which uses key=value
+// arguments
+func addAlterConfigFlags(command *cobra.Command) {
+	command.Flags().StringArray("delete-key", []string{}, "Delete a config, reverting it to its default value. Can be specified multiple times")
+	command.Flags().StringArray("append", []string{}, "Append a value to a list config, example: --append cleanup.policy=compact")
+	command.Flags().StringArray("subtract", []string{}, "Remove a value from a list config, example: --subtract cleanup.policy=compact")
+}
+
+// getAlterConfigs returns the incremental operations of the key=value arguments and of the flags
+func getAlterConfigs(command *cobra.Command, args []string) ([]kadm.AlterConfig, error) {
+	var configs []kadm.AlterConfig
+	operations := []struct {
+		flag   string
+		op     kadm.IncrementalOp
+		values []string
+	}{
+		{"", kadm.SetConfig, args},
+		{"append", kadm.AppendConfig, nil},
+		{"subtract", kadm.SubtractConfig, nil},
+	}
+	for _, operation := range operations {
+		values := operation.values
+		if operation.flag != "" {
+			var err error
+			values, err = command.Flags().GetStringArray(operation.flag)
+			cobra.CheckErr(err)
+		}
+		for _, arg := range values {
+			key, value, ok := strings.Cut(arg, "=")
+			if !ok || key == "" {
+				if operation.flag == "" {
+					return nil, fmt.Errorf("invalid config '%s'. Expected key=value", arg)
+				}
+				return nil, fmt.Errorf("invalid --%s '%s'. Expected key=value", operation.flag, arg)
+			}
+			configs = append(configs, kadm.AlterConfig{Op: operation.op, Name: key, Value: &value})
+		}
+	}
+
+	deletedKeys, err := command.Flags().GetStringArray("delete-key")
+	cobra.CheckErr(err)
+	for _, key := range deletedKeys {
+		configs = append(configs, kadm.AlterConfig{Op: kadm.DeleteConfig, Name: key})
+	}
+
+	if len(configs) == 0 {
+		return nil, errors.New("no config to alter. Use key=value arguments, --delete-key, --append or --subtract")
+	}
+	return configs, nil
+}
+
+// resourceConfigOn returns the config of the resource with the name from the response to describing configs
+func resourceConfigOn(resourceConfigs kadm.ResourceConfigs, err error, name string, resource string) (kadm.ResourceConfig, error) {
+	if err != nil {
+		return kadm.ResourceConfig{}, fmt.Errorf("error describing config of %s: %v", resource, err)
+	}
+	resourceConfig, err := resourceConfigs.On(name, nil)
+	if err == nil {
+		err = resourceConfig.Err
+	}
+	if err != nil {
+		return kadm.ResourceConfig{}, fmt.Errorf("error describing config of %s: %v", resource, err)
+	}
+	return resourceConfig, nil
+}
+
+// printConfigDiff prints the values of the altered configs before and after altering them
+func printConfigDiff(out io.Writer, configs []kadm.AlterConfig, before kadm.ResourceConfig, after kadm.ResourceConfig) {
+	_, err := fmt.Fprintf(out, "%-40s%-30s%-30s\n", "Config", "Before", "After")
+	cobra.CheckErr(err)
+	printed := make(map[string]bool)
+	for _, config := range configs {
+		if printed[config.Name] {
+			continue
+		}
+		printed[config.Name] = true
+		_, err := fmt.Fprintf(out, "%-40s%-30s%-30s\n", config.Name, configValue(before, config.Name), configValue(after, config.Name))
+		cobra.CheckErr(err)
+	}
+}
+
+func configValue(resourceConfig kadm.ResourceConfig, key string) string {
+	for _, config := range resourceConfig.Configs {
+		if config.Key == key {
+			return cmd.FormatConfigValue(config)
+		}
+	}
+	return "-"
+}
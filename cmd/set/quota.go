@@ -0,0 +1,150 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var quotaCmd = &cobra.Command{
+	Use:   "quota [--user <user>] [--client-id <client_id>] [--ip <ip>] [--default-user] [--default-client-id] [--default-ip] [key=value ...] [--delete-key <key>]",
+	Short: "Alter the client quotas of a user, client id or IP",
+	Long: `Alter the client quotas of a user, a client id, both, or an IP, or of their defaults.
+
+Quotas are altered incrementally: quotas which are not specified keep their value.
+- kacao set quota --user alice producer_byte_rate=1048576 consumer_byte_rate=2097152
+- kacao set quota --user alice --client-id billing-service request_percentage=50
+- kacao set quota --default-client-id consumer_byte_rate=10485760
+- kacao set quota --ip 10.0.0.1 connection_creation_rate=10
+
+Quotas are removed with --delete-key:
+- kacao set quota --user alice --delete-key producer_byte_rate
+
+The quotas of users and client ids are producer_byte_rate, consumer_byte_rate, request_percentage and
+controller_mutation_rate, the quota of IPs is connection_creation_rate. The values of the altered quotas are printed
+before and after the change.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(command *cobra.Command, args []string) error {
+		entity, err := cmd.GetQuotaEntity(command)
+		if err != nil {
+			return err
+		}
+		if len(entity) == 0 {
+			return errors.New("a user, client id or IP is required. Use --user, --client-id, --ip or their --default- flag")
+		}
+		ops, err := getAlterQuotaOps(command, args)
+		if err != nil {
+			return err
+		}
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		ctx := context.Background()
+		entityName := cmd.FormatQuotaEntity(entity)
+
+		before, err := describeQuotas(ctx, adminClient, entity)
+		if err != nil {
+			return err
+		}
+		responses, err := adminClient.AlterClientQuotas(ctx, []kadm.AlterClientQuotaEntry{{Entity: entity, Ops: ops}})
+		if err != nil {
+			return fmt.Errorf("error altering quotas of %s: %v", entityName, err)
+		}
+		for _, response := range responses {
+			if response.Err == nil {
+				continue
+			}
+			if response.ErrMessage != "" {
+				return fmt.Errorf("error altering quotas of %s: %v: %s", entityName, response.Err, response.ErrMessage)
+			}
+			return fmt.Errorf("error altering quotas of %s: %v", entityName, response.Err)
+		}
+		after, err := describeQuotas(ctx, adminClient, entity)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "Altered quotas of %s\n", entityName)
+		cobra.CheckErr(err)
+		_, err = fmt.Fprintf(command.OutOrStdout(), "%-40s%-30s%-30s\n", "Quota", "Before", "After")
+		cobra.CheckErr(err)
+		printed := make(map[string]bool)
+		for _, op := range ops {
+			if printed[op.Key] {
+				continue
+			}
+			printed[op.Key] = true
+			_, err := fmt.Fprintf(command.OutOrStdout(), "%-40s%-30s%-30s\n", op.Key, quotaValue(before, op.Key), quotaValue(after, op.Key))
+			cobra.CheckErr(err)
+		}
+		return nil
+	},
+}
+
+// getAlterQuotaOps returns the quotas to set of the key=value arguments and the quotas to remove of --delete-key
+func getAlterQuotaOps(command *cobra.Command, args []string) ([]kadm.AlterClientQuotaOp, error) {
+	var ops []kadm.AlterClientQuotaOp
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid quota '%s'. Expected key=value", arg)
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quota '%s'. The value must be a number", arg)
+		}
+		ops = append(ops, kadm.AlterClientQuotaOp{Key: key, Value: number})
+	}
+
+	deletedKeys, err := command.Flags().GetStringArray("delete-key")
+	cobra.CheckErr(err)
+	for _, key := range deletedKeys {
+		ops = append(ops, kadm.AlterClientQuotaOp{Key: key, Remove: true})
+	}
+
+	if len(ops) == 0 {
+		return nil, errors.New("no quota to alter. Use key=value arguments or --delete-key")
+	}
+	return ops, nil
+}
+
+// describeQuotas returns the formatted quotas of exactly the entity, by key
+func describeQuotas(ctx context.Context, adminClient *kadm.Client, entity kadm.ClientQuotaEntity) (map[string]string, error) {
+	quotas, err := adminClient.DescribeClientQuotas(ctx, true, cmd.QuotaEntityFilter(entity))
+	if err != nil {
+		return nil, fmt.Errorf("error describing quotas of %s: %v", cmd.FormatQuotaEntity(entity), err)
+	}
+	values := make(map[string]string)
+	for _, quota := range quotas {
+		for _, value := range quota.Values {
+			values[value.Key] = cmd.FormatQuotaValue(value.Value)
+		}
+	}
+	return values, nil
+}
+
+func quotaValue(values map[string]string, key string) string {
+	if value, ok := values[key]; ok {
+		return value
+	}
+	return "-"
+}
+
+func init() {
+	cmd.AddQuotaEntityFlags(quotaCmd)
+	quotaCmd.Flags().StringArray("delete-key", []string{}, "Remove a quota. Can be specified multiple times")
+	setCmd.AddCommand(quotaCmd)
+}
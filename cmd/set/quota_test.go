@@ -0,0 +1,198 @@
+package set
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestSetQuota(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	alice := "alice"
+	billing := "billing-service"
+	tests := []struct {
+		name             string
+		initialQuotas    []kadm.AlterClientQuotaEntry
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+		// Quotas of the entity of the command after it
+		entity         kadm.ClientQuotaEntity
+		expectedQuotas map[string]float64
+	}{
+		{
+			name: "set quotas of a user",
+			args: []string{"set", "quota", "--user", "alice", "producer_byte_rate=1048576", "consumer_byte_rate=2097152"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Altered quotas of user=alice\nQuota\s+Before\s+After\s+\nproducer_byte_rate\s+-\s+1048576\s+\nconsumer_byte_rate\s+-\s+2097152\s+\n$`),
+			},
+			entity:         kadm.ClientQuotaEntity{{Type: "user", Name: &alice}},
+			expectedQuotas: map[string]float64{"producer_byte_rate": 1048576, "consumer_byte_rate": 2097152},
+		},
+		{
+			name: "set quota of a user and client id",
+			initialQuotas: []kadm.AlterClientQuotaEntry{{
+				Entity: kadm.ClientQuotaEntity{{Type: "user", Name: &alice}, {Type: "client-id", Name: &billing}},
+				Ops:    []kadm.AlterClientQuotaOp{{Key: "request_percentage", Value: 50}},
+			}},
+			args: []string{"set", "quota", "--user", "alice", "--client-id", "billing-service", "request_percentage=25"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Altered quotas of user=alice, client-id=billing-service\nQuota\s+Before\s+After\s+\nrequest_percentage\s+50\s+25\s+\n$`),
+			},
+			entity:         kadm.ClientQuotaEntity{{Type: "user", Name: &alice}, {Type: "client-id", Name: &billing}},
+			expectedQuotas: map[string]float64{"request_percentage": 25},
+		},
+		{
+			name: "remove a quota",
+			initialQuotas: []kadm.AlterClientQuotaEntry{{
+				Entity: kadm.ClientQuotaEntity{{Type: "client-id"}},
+				Ops:    []kadm.AlterClientQuotaOp{{Key: "producer_byte_rate", Value: 1048576}, {Key: "consumer_byte_rate", Value: 1048576}},
+			}},
+			args: []string{"set", "quota", "--default-client-id", "--delete-key", "producer_byte_rate"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`^Altered quotas of client-id=<default>\nQuota\s+Before\s+After\s+\nproducer_byte_rate\s+1048576\s+-\s+\n$`),
+			},
+			entity:         kadm.ClientQuotaEntity{{Type: "client-id"}},
+			expectedQuotas: map[string]float64{"consumer_byte_rate": 1048576},
+		},
+		{
+			name:          "unknown quota",
+			args:          []string{"set", "quota", "--user", "alice", "bytes_per_day=1"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: error altering quotas of user=alice: \S+`),
+			},
+		},
+		{
+			name:          "invalid quota value",
+			args:          []string{"set", "quota", "--user", "alice", "producer_byte_rate=fast"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid quota 'producer_byte_rate=fast'. The value must be a number`),
+			},
+		},
+		{
+			name:          "no entity",
+			args:          []string{"set", "quota", "producer_byte_rate=1048576"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: a user, client id or IP is required. Use --user, --client-id, --ip or their --default- flag`),
+			},
+		},
+		{
+			name:          "no quota",
+			args:          []string{"set", "quota", "--user", "alice"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: no quota to alter. Use key=value arguments or --delete-key`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+			defer func() {
+				quotas, err := adminClient.DescribeClientQuotas(ctx, false, nil)
+				assert.NoError(t, err)
+				var entries []kadm.AlterClientQuotaEntry
+				for _, quota := range quotas {
+					entry := kadm.AlterClientQuotaEntry{Entity: quota.Entity}
+					for _, value := range quota.Values {
+						entry.Ops = append(entry.Ops, kadm.AlterClientQuotaOp{Key: value.Key, Remove: true})
+					}
+					entries = append(entries, entry)
+				}
+				if len(entries) > 0 {
+					_, err = adminClient.AlterClientQuotas(ctx, entries)
+					assert.NoError(t, err)
+				}
+			}()
+
+			if tt.initialQuotas != nil {
+				responses, err := adminClient.AlterClientQuotas(ctx, tt.initialQuotas)
+				assert.NoError(t, err)
+				for _, response := range responses {
+					assert.NoError(t, response.Err)
+				}
+			}
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+
+			if tt.expectedQuotas == nil {
+				return
+			}
+			var components []kadm.DescribeClientQuotaComponent
+			for _, component := range tt.entity {
+				if component.Name == nil {
+					components = append(components, kadm.DescribeClientQuotaComponent{Type: component.Type, MatchType: 1})
+				} else {
+					components = append(components, kadm.DescribeClientQuotaComponent{Type: component.Type, MatchName: component.Name})
+				}
+			}
+			quotas, err := adminClient.DescribeClientQuotas(ctx, true, components)
+			assert.NoError(t, err)
+			actualQuotas := make(map[string]float64)
+			for _, quota := range quotas {
+				for _, value := range quota.Values {
+					actualQuotas[value.Key] = value.Value
+				}
+			}
+			assert.Equal(t, tt.expectedQuotas, actualQuotas)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package set
+
+import (
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set properties of a resource",
+	Long:  `Set properties of a resource`,
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Alter the configuration of a resource",
+	Long:  `Alter the configuration of a resource`,
+}
+
+func init() {
+	setCmd.AddCommand(configCmd)
+	cmd.RootCmd.AddCommand(setCmd)
+}
@@ -0,0 +1,75 @@
+package set
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Vidalee/kacao/cmd"
+	"github.com/spf13/cobra"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var topicCmd = &cobra.Command{
+	Use:   "topic <topic_name> [key=value ...] [--delete-key <key>] [--append <key=value>] [--subtract <key=value>]",
+	Short: "Alter the configuration of a topic",
+	Long: `Alter the configuration of a topic
+
+Configs are altered incrementally: configs which are not specified keep their value.
+- kacao set config topic orders retention.ms=86400000 cleanup.policy=delete
+- kacao set config topic orders --delete-key retention.ms
+- kacao set config topic orders --append cleanup.policy=compact
+
+Deleted configs revert to the default of the broker. --append and --subtract add and remove values of list configs,
+such as cleanup.policy. The values of the altered configs are printed before and after the change.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(command *cobra.Command, args []string) error {
+		topic := args[0]
+		configs, err := getAlterConfigs(command, args[1:])
+		if err != nil {
+			return err
+		}
+
+		boostrapServers, err := cmd.GetCurrentClusterBootstrapServers()
+		cobra.CheckErr(err)
+		cl, err := kgo.NewClient(kgo.SeedBrokers(boostrapServers...))
+		cobra.CheckErr(err)
+		adminClient := kadm.NewClient(cl)
+		defer cl.Close()
+		defer adminClient.Close()
+
+		ctx := context.Background()
+
+		before, err := describeTopicConfig(ctx, adminClient, topic)
+		if err != nil {
+			return err
+		}
+		responses, err := adminClient.AlterTopicConfigs(ctx, configs, topic)
+		if err == nil {
+			err = cmd.AlterConfigsError(responses)
+		}
+		if err != nil {
+			return fmt.Errorf("error altering config of topic '%s': %v", topic, err)
+		}
+		after, err := describeTopicConfig(ctx, adminClient, topic)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(command.OutOrStdout(), "Altered config of topic '%s'\n", topic)
+		cobra.CheckErr(err)
+		printConfigDiff(command.OutOrStdout(), configs, before, after)
+		return nil
+	},
+}
+
+func describeTopicConfig(ctx context.Context, adminClient *kadm.Client, topic string) (kadm.ResourceConfig, error) {
+	resourceConfigs, err := adminClient.DescribeTopicConfigs(ctx, topic)
+	return resourceConfigOn(resourceConfigs, err, topic, fmt.Sprintf("topic '%s'", topic))
+}
+
+func init() {
+	addAlterConfigFlags(topicCmd)
+
+	configCmd.AddCommand(topicCmd)
+}
@@ -0,0 +1,187 @@
+package set
+
+import (
+	"context"
+	"fmt"
+	"github.com/Vidalee/kacao/test_helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"regexp"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestSetTopicConfig(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("test-cluster"),
+	)
+	defer func() {
+		if err := testcontainers.TerminateContainer(kafkaContainer); err != nil {
+			fmt.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	if err != nil {
+		fmt.Printf("failed to start container: %s", err)
+		return
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	assert.NoError(t, err)
+	cl, err := kgo.NewClient(
+		kgo.SeedBrokers(brokers...),
+		kgo.ConsumerGroup("kacao-test-group"),
+	)
+	assert.NoError(t, err)
+	adminClient := kadm.NewClient(cl)
+	defer cl.Close()
+	defer adminClient.Close()
+
+	testConfig := test_helpers.TestConfig{
+		Clusters: map[string]map[string]interface{}{
+			"test-cluster": {
+				"bootstrap-servers": brokers,
+			},
+		},
+		Contexts: map[string]map[string]interface{}{
+			"test-context": {
+				"cluster":        "test-cluster",
+				"consumer-group": "test-group",
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		createTopic      string
+		topicOptions     map[string]*string
+		args             []string
+		expectedError    bool
+		expectedPatterns []*regexp.Regexp
+		// Configs of the topic after the command, nil for configs which are not set on the topic
+		expectedConfigs map[string]*string
+	}{
+		{
+			name:        "set configs",
+			createTopic: "set-topic",
+			args:        []string{"set", "config", "topic", "set-topic", "retention.ms=86400000", "cleanup.policy=compact"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Altered config of topic 'set-topic'\nConfig\s+Before\s+After\s+\nretention.ms\s+\d+\s+86400000\s+\ncleanup.policy\s+delete\s+compact\s+\n$`),
+			},
+			expectedConfigs: map[string]*string{
+				"retention.ms":   test_helpers.StringPtr("86400000"),
+				"cleanup.policy": test_helpers.StringPtr("compact"),
+			},
+		},
+		{
+			name:         "delete a config",
+			createTopic:  "delete-key-topic",
+			topicOptions: map[string]*string{"retention.ms": test_helpers.StringPtr("1000")},
+			args:         []string{"set", "config", "topic", "delete-key-topic", "--delete-key", "retention.ms"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`retention.ms\s+1000\s+\d+\s+\n$`),
+			},
+			expectedConfigs: map[string]*string{"retention.ms": nil},
+		},
+		{
+			name:         "append a list value",
+			createTopic:  "append-topic",
+			topicOptions: map[string]*string{"cleanup.policy": test_helpers.StringPtr("delete")},
+			args:         []string{"set", "config", "topic", "append-topic", "--append", "cleanup.policy=compact"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`cleanup.policy\s+delete\s+delete,compact\s+\n$`),
+			},
+			expectedConfigs: map[string]*string{"cleanup.policy": test_helpers.StringPtr("delete,compact")},
+		},
+		{
+			name:         "subtract a list value",
+			createTopic:  "subtract-topic",
+			topicOptions: map[string]*string{"cleanup.policy": test_helpers.StringPtr("delete,compact")},
+			args:         []string{"set", "config", "topic", "subtract-topic", "--subtract", "cleanup.policy=delete"},
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`cleanup.policy\s+delete,compact\s+compact\s+\n$`),
+			},
+			expectedConfigs: map[string]*string{"cleanup.policy": test_helpers.StringPtr("compact")},
+		},
+		{
+			name:          "invalid config value",
+			createTopic:   "invalid-value-topic",
+			args:          []string{"set", "config", "topic", "invalid-value-topic", "retention.ms=forever"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: error altering config of topic 'invalid-value-topic': INVALID_CONFIG`),
+			},
+		},
+		{
+			name:          "unknown topic",
+			args:          []string{"set", "config", "topic", "unknown-topic", "retention.ms=1000"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: error describing config of topic 'unknown-topic': UNKNOWN_TOPIC_OR_PARTITION`),
+			},
+		},
+		{
+			name:          "invalid config argument",
+			args:          []string{"set", "config", "topic", "set-topic", "retention.ms"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: invalid config 'retention.ms'. Expected key=value`),
+			},
+		},
+		{
+			name:          "no config",
+			args:          []string{"set", "config", "topic", "set-topic"},
+			expectedError: true,
+			expectedPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`Error: no config to alter. Use key=value arguments, --delete-key, --append or --subtract`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := test_helpers.SetupTest(t, testConfig)
+			defer test_helpers.CleanupTestConfig(t, tempDir)
+			defer test_helpers.CleanupKafkaCluster(t, cl, adminClient, ctx)
+
+			if tt.createTopic != "" {
+				_, err := adminClient.CreateTopics(ctx, 1, 1, tt.topicOptions, tt.createTopic)
+				assert.NoError(t, err)
+			}
+
+			output, err := test_helpers.ExecuteCommandWrapper(tt.args)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, pattern := range tt.expectedPatterns {
+				assert.Regexp(t, pattern, output)
+			}
+
+			if tt.expectedConfigs == nil {
+				return
+			}
+			resourceConfigs, err := adminClient.DescribeTopicConfigs(ctx, tt.createTopic)
+			assert.NoError(t, err)
+			resourceConfig, err := resourceConfigs.On(tt.createTopic, nil)
+			assert.NoError(t, err)
+			for _, config := range resourceConfig.Configs {
+				expectedValue, ok := tt.expectedConfigs[config.Key]
+				if !ok {
+					continue
+				}
+				if expectedValue == nil {
+					assert.NotEqual(t, "DYNAMIC_TOPIC_CONFIG", config.Source.String(), "Expected config '%s' not to be set on the topic", config.Key)
+					continue
+				}
+				assert.Equal(t, *expectedValue, *config.Value)
+			}
+		})
+	}
+}
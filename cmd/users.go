@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+// ScramMechanismsUsage lists the SCRAM mechanisms of user credentials
+const ScramMechanismsUsage = "SCRAM-SHA-256 or SCRAM-SHA-512"
+
+// ParseScramMechanism parses the name of a SCRAM mechanism, ignoring its case
+func ParseScramMechanism(name string) (kadm.ScramMechanism, error) {
+	for _, mechanism := range []kadm.ScramMechanism{kadm.ScramSha256, kadm.ScramSha512} {
+		if strings.EqualFold(name, mechanism.String()) {
+			return mechanism, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown SCRAM mechanism '%s'. Use %s", name, ScramMechanismsUsage)
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func TestParseScramMechanism(t *testing.T) {
+	mechanism, err := ParseScramMechanism("SCRAM-SHA-512")
+	assert.NoError(t, err)
+	assert.Equal(t, kadm.ScramSha512, mechanism)
+
+	mechanism, err = ParseScramMechanism("scram-sha-256")
+	assert.NoError(t, err)
+	assert.Equal(t, kadm.ScramSha256, mechanism)
+
+	_, err = ParseScramMechanism("PLAIN")
+	assert.EqualError(t, err, "unknown SCRAM mechanism 'PLAIN'. Use SCRAM-SHA-256 or SCRAM-SHA-512")
+}
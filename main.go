@@ -7,8 +7,11 @@ import (
 	_ "github.com/Vidalee/kacao/cmd/create"
 	_ "github.com/Vidalee/kacao/cmd/delete"
 	_ "github.com/Vidalee/kacao/cmd/describe"
+	_ "github.com/Vidalee/kacao/cmd/elect"
 	_ "github.com/Vidalee/kacao/cmd/get"
 	_ "github.com/Vidalee/kacao/cmd/produce"
+	_ "github.com/Vidalee/kacao/cmd/reassign"
+	_ "github.com/Vidalee/kacao/cmd/set"
 )
 
 func main() {
@@ -0,0 +1,264 @@
+package serde
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+func (d *SchemaRegistryDeserializer) avroDecoder(ctx context.Context, schema sr.Schema) (schemaDecoder, error) {
+	avroSchema, err := parseAvro(ctx, d.client, schema)
+	if err != nil {
+		return nil, err
+	}
+	return func(payload []byte) (any, error) {
+		var value any
+		err := avro.Unmarshal(avroSchema, payload, &value)
+		return value, err
+	}, nil
+}
+
+// parseAvro parses a schema of the registry, after the named types it references
+func parseAvro(ctx context.Context, client *sr.Client, schema sr.Schema) (avro.Schema, error) {
+	cache := &avro.SchemaCache{}
+	if err := parseAvroReferences(ctx, client, schema.References, cache); err != nil {
+		return nil, err
+	}
+	return avro.ParseWithCache(schema.Schema, "", cache)
+}
+
+// parseAvroReferences parses the named types referenced by a schema into cache, depth first
+func parseAvroReferences(ctx context.Context, client *sr.Client, references []sr.SchemaReference, cache *avro.SchemaCache) error {
+	for _, reference := range references {
+		referenced, err := client.SchemaByVersion(ctx, reference.Subject, reference.Version)
+		if err != nil {
+			return fmt.Errorf("error fetching referenced schema '%s' version %d: %v", reference.Subject, reference.Version, err)
+		}
+		if err := parseAvroReferences(ctx, client, referenced.References, cache); err != nil {
+			return err
+		}
+		if _, err := avro.ParseWithCache(referenced.Schema.Schema, "", cache); err != nil {
+			return fmt.Errorf("invalid referenced schema '%s' version %d: %v", reference.Subject, reference.Version, err)
+		}
+	}
+	return nil
+}
+
+// avroNative converts a value decoded from JSON, with numbers as json.Number, to the Go value expected by the Avro
+// encoder for schema. Unions accept either a value of one of their types, or the Avro JSON encoding: an object with
+// the name of the type as its single key.
+func avroNative(schema avro.Schema, value any) (any, error) {
+	switch s := schema.(type) {
+	case *avro.RefSchema:
+		return avroNative(s.Schema(), value)
+	case *avro.PrimitiveSchema:
+		return avroPrimitive(s, value)
+	case *avro.RecordSchema:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an object for record %s, got %s", s.FullName(), jsonType(value))
+		}
+		record := make(map[string]any, len(object))
+		for _, field := range s.Fields() {
+			fieldValue, ok := object[field.Name()]
+			if !ok {
+				if field.HasDefault() {
+					continue
+				}
+				return nil, fmt.Errorf("missing field '%s' of record %s", field.Name(), s.FullName())
+			}
+			converted, err := avroNative(field.Type(), fieldValue)
+			if err != nil {
+				return nil, fmt.Errorf("field '%s': %v", field.Name(), err)
+			}
+			record[field.Name()] = converted
+		}
+		return record, nil
+	case *avro.EnumSchema:
+		symbol, ok := value.(string)
+		if !ok || !slices.Contains(s.Symbols(), symbol) {
+			return nil, fmt.Errorf("expected one of the symbols %v of enum %s, got %v", s.Symbols(), s.FullName(), value)
+		}
+		return symbol, nil
+	case *avro.FixedSchema:
+		text, ok := value.(string)
+		if !ok || len(text) != s.Size() {
+			return nil, fmt.Errorf("expected a string of %d bytes for fixed %s, got %v", s.Size(), s.FullName(), value)
+		}
+		fixed := reflect.New(reflect.ArrayOf(s.Size(), reflect.TypeFor[byte]())).Elem()
+		reflect.Copy(fixed, reflect.ValueOf([]byte(text)))
+		return fixed.Interface(), nil
+	case *avro.ArraySchema:
+		items, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an array, got %s", jsonType(value))
+		}
+		array := make([]any, 0, len(items))
+		for i, item := range items {
+			converted, err := avroNative(s.Items(), item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %v", i, err)
+			}
+			array = append(array, converted)
+		}
+		return array, nil
+	case *avro.MapSchema:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an object for map, got %s", jsonType(value))
+		}
+		converted := make(map[string]any, len(object))
+		for key, item := range object {
+			convertedItem, err := avroNative(s.Values(), item)
+			if err != nil {
+				return nil, fmt.Errorf("key '%s': %v", key, err)
+			}
+			converted[key] = convertedItem
+		}
+		return converted, nil
+	case *avro.UnionSchema:
+		return avroUnion(s, value)
+	}
+	return nil, fmt.Errorf("unsupported schema type %s", schema.Type())
+}
+
+func avroUnion(schema *avro.UnionSchema, value any) (any, error) {
+	if value == nil {
+		if schema.Nullable() {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("null is not allowed by union %s", schema.String())
+	}
+	if object, ok := value.(map[string]any); ok && len(object) == 1 {
+		for name, branchValue := range object {
+			for _, branch := range schema.Types() {
+				if name == avroTypeName(branch) || name == string(branch.Type()) {
+					converted, err := avroNative(branch, branchValue)
+					if err != nil {
+						return nil, err
+					}
+					return map[string]any{avroTypeName(branch): converted}, nil
+				}
+			}
+		}
+	}
+	for _, branch := range schema.Types() {
+		if branch.Type() == avro.Null {
+			continue
+		}
+		if converted, err := avroNative(branch, value); err == nil {
+			return map[string]any{avroTypeName(branch): converted}, nil
+		}
+	}
+	return nil, fmt.Errorf("%v does not match any type of union %s", value, schema.String())
+}
+
+// avroTypeName is the name of a type in a union, as expected by the Avro encoder
+func avroTypeName(schema avro.Schema) string {
+	if ref, ok := schema.(*avro.RefSchema); ok {
+		schema = ref.Schema()
+	}
+	if named, ok := schema.(avro.NamedSchema); ok {
+		return named.FullName()
+	}
+	if primitive, ok := schema.(*avro.PrimitiveSchema); ok && primitive.Logical() != nil {
+		return string(schema.Type()) + "." + string(primitive.Logical().Type())
+	}
+	return string(schema.Type())
+}
+
+func avroPrimitive(schema *avro.PrimitiveSchema, value any) (any, error) {
+	var logicalType avro.LogicalType
+	if schema.Logical() != nil {
+		logicalType = schema.Logical().Type()
+	}
+	number, isNumber := value.(json.Number)
+	text, isText := value.(string)
+
+	switch {
+	case schema.Type() == avro.Null && value == nil:
+		return nil, nil
+	case schema.Type() == avro.Boolean:
+		if boolean, ok := value.(bool); ok {
+			return boolean, nil
+		}
+	case logicalType == avro.Decimal && (isNumber || isText):
+		decimal, ok := new(big.Rat).SetString(string(number) + text)
+		if ok {
+			return decimal, nil
+		}
+	case (logicalType == avro.TimestampMillis || logicalType == avro.TimestampMicros || logicalType == avro.Date) && isText:
+		timestamp, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return nil, fmt.Errorf("expected a RFC3339 timestamp for %s, got '%s'", logicalType, text)
+		}
+		return timestamp, nil
+	case schema.Type() == avro.Int && isNumber:
+		integer, err := strconv.ParseInt(string(number), 10, 32)
+		if err == nil {
+			return int(integer), nil
+		}
+	case schema.Type() == avro.Long && isNumber:
+		integer, err := number.Int64()
+		if err == nil {
+			return integer, nil
+		}
+	case schema.Type() == avro.Float && isNumber:
+		float, err := strconv.ParseFloat(string(number), 32)
+		if err == nil {
+			return float32(float), nil
+		}
+	case schema.Type() == avro.Double && isNumber:
+		float, err := number.Float64()
+		if err == nil {
+			return float, nil
+		}
+	case schema.Type() == avro.String && isText:
+		return text, nil
+	case schema.Type() == avro.Bytes && isText:
+		return []byte(text), nil
+	}
+	return nil, fmt.Errorf("expected %s, got %v", avroTypeName(schema), value)
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case json.Number:
+		return "a number"
+	case string:
+		return "a string"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// encodeAvro encodes a JSON value with schema
+func encodeAvro(schema avro.Schema, jsonValue []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(jsonValue))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	native, err := avroNative(schema, value)
+	if err != nil {
+		return nil, err
+	}
+	return avro.Marshal(schema, native)
+}
@@ -0,0 +1,57 @@
+package serde
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hamba/avro/v2"
+)
+
+// TextDeserializer renders data as is
+type TextDeserializer struct{}
+
+func (TextDeserializer) Deserialize(data []byte) (string, error) {
+	return string(data), nil
+}
+
+// JSONDeserializer validates and compacts JSON data. Data which is not JSON is left to the encoding flags.
+type JSONDeserializer struct{}
+
+func (JSONDeserializer) Deserialize(data []byte) (string, error) {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, data); err != nil {
+		return "", ErrNotWireFormat
+	}
+	return compacted.String(), nil
+}
+
+// AvroDeserializer decodes raw Avro data, without the schema registry wire format, with a schema file
+type AvroDeserializer struct {
+	schema avro.Schema
+}
+
+func NewAvroDeserializer(path string) (*AvroDeserializer, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema '%s': %v", path, err)
+	}
+	schema, err := avro.Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema '%s': %v", path, err)
+	}
+	return &AvroDeserializer{schema: schema}, nil
+}
+
+func (d *AvroDeserializer) Deserialize(data []byte) (string, error) {
+	var value any
+	if err := avro.Unmarshal(d.schema, data, &value); err != nil {
+		return "", fmt.Errorf("error decoding Avro data: %v", err)
+	}
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("error decoding Avro data: %v", err)
+	}
+	return string(jsonValue), nil
+}
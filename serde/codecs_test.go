@@ -0,0 +1,61 @@
+package serde
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextDeserializer(t *testing.T) {
+	text, err := TextDeserializer{}.Deserialize([]byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", text)
+}
+
+func TestJSONDeserializer(t *testing.T) {
+	text, err := JSONDeserializer{}.Deserialize([]byte(`{ "id": 1,  "tags": [ "a" ] }`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1,"tags":["a"]}`, text)
+
+	_, err = JSONDeserializer{}.Deserialize([]byte(`{"id": `))
+	assert.ErrorIs(t, err, ErrNotWireFormat)
+}
+
+func TestAvroDeserializer(t *testing.T) {
+	invalidPath := filepath.Join(t.TempDir(), "invalid.avsc")
+	assert.NoError(t, os.WriteFile(invalidPath, []byte(`{"type": "unknown"}`), 0644))
+
+	path := filepath.Join(t.TempDir(), "item.avsc")
+	assert.NoError(t, os.WriteFile(path, []byte(itemSchema), 0644))
+	deserializer, err := NewAvroDeserializer(path)
+	assert.NoError(t, err)
+	item, err := avro.Marshal(avro.MustParse(itemSchema), map[string]any{"name": "book"})
+	assert.NoError(t, err)
+	text, err := deserializer.Deserialize(item)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name": "book"}`, text)
+
+	_, err = deserializer.Deserialize([]byte{0x10})
+	assert.ErrorContains(t, err, "error decoding Avro data")
+
+	_, err = NewAvroDeserializer(invalidPath)
+	assert.ErrorContains(t, err, "invalid schema")
+	_, err = NewAvroDeserializer(filepath.Join(t.TempDir(), "missing.avsc"))
+	assert.ErrorContains(t, err, "error reading schema")
+}
+
+func TestExecDeserializer(t *testing.T) {
+	text, err := NewExecDeserializer("tr a-z A-Z").Deserialize([]byte("hello\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "HELLO", text)
+
+	text, err = NewExecDeserializer("wc -c | tr -d ' '").Deserialize([]byte{0x00, 0xff, 0x01})
+	assert.NoError(t, err)
+	assert.Equal(t, "3", text)
+
+	_, err = NewExecDeserializer("echo 'unknown format' >&2; exit 3").Deserialize([]byte("hello"))
+	assert.EqualError(t, err, "error running 'echo 'unknown format' >&2; exit 3': exit status 3: unknown format")
+}
@@ -0,0 +1,55 @@
+package serde
+
+import (
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// LoadDescriptorSet reads a FileDescriptorSet, as written by 'protoc --include_imports --descriptor_set_out' or
+// 'buf build -o'
+func LoadDescriptorSet(path string) (*protoregistry.Files, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading descriptor set '%s': %v", path, err)
+	}
+	var descriptorSet descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(content, &descriptorSet); err != nil {
+		return nil, fmt.Errorf("invalid descriptor set '%s': %v", path, err)
+	}
+	files, err := protodesc.NewFiles(&descriptorSet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set '%s': %v", path, err)
+	}
+	return files, nil
+}
+
+// ProtobufDeserializer decodes raw Protobuf messages of a single type to JSON, without a schema registry
+type ProtobufDeserializer struct {
+	descriptor protoreflect.MessageDescriptor
+}
+
+func NewProtobufDeserializer(files *protoregistry.Files, message string) (*ProtobufDeserializer, error) {
+	found, err := files.FindDescriptorByName(protoreflect.FullName(message))
+	if err != nil {
+		return nil, fmt.Errorf("no message '%s' in the descriptor set", message)
+	}
+	descriptor, ok := found.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a message", message)
+	}
+	return &ProtobufDeserializer{descriptor: descriptor}, nil
+}
+
+func (d *ProtobufDeserializer) Deserialize(data []byte) (string, error) {
+	jsonValue, err := DecodeProtobuf(d.descriptor, data)
+	if err != nil {
+		return "", fmt.Errorf("error decoding data as %s: %v", d.descriptor.FullName(), err)
+	}
+	return string(jsonValue), nil
+}
@@ -0,0 +1,84 @@
+package serde
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// writeDescriptorSet compiles orders.proto and writes it with its imports to a descriptor set file
+func writeDescriptorSet(t *testing.T) string {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{Accessor: protocompile.SourceAccessorFromMap(map[string]string{
+			"orders.proto":   ordersProto,
+			"customer.proto": customerProto,
+		})}),
+	}
+	files, err := compiler.Compile(context.Background(), "orders.proto")
+	assert.NoError(t, err)
+
+	var descriptorSet descriptorpb.FileDescriptorSet
+	var add func(file protoreflect.FileDescriptor)
+	add = func(file protoreflect.FileDescriptor) {
+		for i := 0; i < file.Imports().Len(); i++ {
+			add(file.Imports().Get(i).FileDescriptor)
+		}
+		descriptorSet.File = append(descriptorSet.File, protodesc.ToFileDescriptorProto(file))
+	}
+	add(files[0])
+	content, err := proto.Marshal(&descriptorSet)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "orders.pb")
+	assert.NoError(t, os.WriteFile(path, content, 0644))
+	return path
+}
+
+func TestProtobufDeserializer(t *testing.T) {
+	files, err := LoadDescriptorSet(writeDescriptorSet(t))
+	assert.NoError(t, err)
+
+	decoder, err := NewProtobufDeserializer(files, "shop.Order")
+	assert.NoError(t, err)
+	var order []byte
+	order = protowire.AppendTag(order, 1, protowire.BytesType)
+	order = protowire.AppendString(order, "book")
+	order = protowire.AppendTag(order, 2, protowire.VarintType)
+	order = protowire.AppendVarint(order, 3)
+	decoded, err := decoder.Deserialize(order)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"item": "book", "quantity": 3}`, decoded)
+
+	_, err = decoder.Deserialize([]byte{0xff})
+	assert.ErrorContains(t, err, "error decoding data as shop.Order")
+
+	decoder, err = NewProtobufDeserializer(files, "shop.Order.Line")
+	assert.NoError(t, err)
+	var line []byte
+	line = protowire.AppendTag(line, 1, protowire.BytesType)
+	line = protowire.AppendString(line, "B-1")
+	decoded, err = decoder.Deserialize(line)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sku": "B-1"}`, decoded)
+
+	_, err = NewProtobufDeserializer(files, "shop.Unknown")
+	assert.ErrorContains(t, err, "no message 'shop.Unknown' in the descriptor set")
+	_, err = NewProtobufDeserializer(files, "shop.Order.item")
+	assert.ErrorContains(t, err, "'shop.Order.item' is not a message")
+
+	_, err = LoadDescriptorSet(filepath.Join(t.TempDir(), "missing.pb"))
+	assert.ErrorContains(t, err, "error reading descriptor set")
+	invalid := filepath.Join(t.TempDir(), "invalid.pb")
+	assert.NoError(t, os.WriteFile(invalid, []byte("not a descriptor set"), 0644))
+	_, err = LoadDescriptorSet(invalid)
+	assert.ErrorContains(t, err, "invalid descriptor set")
+}
@@ -0,0 +1,181 @@
+package serde
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/twmb/franz-go/pkg/sr"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// LatestVersion is the version of the latest schema of a subject
+const LatestVersion = -1
+
+// SchemaSpec is the schema to encode data with: a version of a subject of the registry, or a schema file which is
+// looked up under the subject
+type SchemaSpec struct {
+	Subject string
+	Version int
+	File    string
+	// Register registers the schema file under the subject if it is not registered yet, instead of failing
+	Register bool
+	// ProtoMessage is the full name of the Protobuf message to encode, the first message of the schema by default
+	ProtoMessage string
+}
+
+// ErrSchemaNotRegistered is returned when a schema file is not registered under the subject to encode data with
+var ErrSchemaNotRegistered = errors.New("schema is not registered")
+
+// SchemaEncoder encodes JSON values with a schema of the registry, to the Confluent wire format
+type SchemaEncoder struct {
+	id      int
+	indexes []int
+	encode  func(jsonValue []byte) ([]byte, error)
+}
+
+func NewSchemaEncoder(client *sr.Client, spec SchemaSpec) (*SchemaEncoder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	var subjectSchema sr.SubjectSchema
+	var err error
+	if spec.File != "" {
+		schema, err := ReadSchemaFile(spec.File)
+		if err != nil {
+			return nil, err
+		}
+		subjectSchema, err = lookupSchema(ctx, client, spec, schema)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		subjectSchema, err = client.SchemaByVersion(ctx, spec.Subject, spec.Version)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching schema of subject '%s': %v", spec.Subject, err)
+		}
+	}
+
+	encoder := &SchemaEncoder{id: subjectSchema.ID}
+	schema := subjectSchema.Schema
+	switch schema.Type {
+	case sr.TypeAvro:
+		avroSchema, err := parseAvro(ctx, client, schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema %d: %v", encoder.id, err)
+		}
+		encoder.encode = func(jsonValue []byte) ([]byte, error) {
+			return encodeAvro(avroSchema, jsonValue)
+		}
+	case sr.TypeProtobuf:
+		file, err := compileProtobuf(ctx, client, encoder.id, schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema %d: %v", encoder.id, err)
+		}
+		descriptor, err := protobufMessageByName(file, spec.ProtoMessage)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema %d: %v", encoder.id, err)
+		}
+		encoder.indexes = protobufIndexes(descriptor)
+		encoder.encode = func(jsonValue []byte) ([]byte, error) {
+			return EncodeProtobuf(descriptor, jsonValue)
+		}
+	case sr.TypeJSON:
+		encoder.encode = encodeJSON
+	default:
+		return nil, fmt.Errorf("unsupported schema type %s", schema.Type)
+	}
+	return encoder, nil
+}
+
+// lookupSchema returns the version of the schema file under the subject, registering it first if spec.Register is set
+func lookupSchema(ctx context.Context, client *sr.Client, spec SchemaSpec, schema sr.Schema) (sr.SubjectSchema, error) {
+	if spec.Register {
+		// Registering a schema which already exists returns it instead of creating a new version
+		subjectSchema, err := client.CreateSchema(ctx, spec.Subject, schema)
+		if err != nil {
+			return sr.SubjectSchema{}, fmt.Errorf("error registering schema '%s' under subject '%s': %v", spec.File, spec.Subject, err)
+		}
+		return subjectSchema, nil
+	}
+	subjectSchema, err := client.LookupSchema(ctx, spec.Subject, schema)
+	var responseError *sr.ResponseError
+	if errors.As(err, &responseError) && (responseError.ErrorCode == sr.ErrSubjectNotFound.Code || responseError.ErrorCode == sr.ErrSchemaNotFound.Code) {
+		return sr.SubjectSchema{}, fmt.Errorf("%w under subject '%s': %s", ErrSchemaNotRegistered, spec.Subject, spec.File)
+	}
+	if err != nil {
+		return sr.SubjectSchema{}, fmt.Errorf("error looking up schema '%s' under subject '%s': %v", spec.File, spec.Subject, err)
+	}
+	return subjectSchema, nil
+}
+
+// Encode encodes a JSON value, prefixed with the magic byte and the schema id
+func (e *SchemaEncoder) Encode(jsonValue []byte) ([]byte, error) {
+	payload, err := e.encode(jsonValue)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding data with schema %d: %v", e.id, err)
+	}
+	data, err := new(sr.ConfluentHeader).AppendEncode(nil, e.id, e.indexes)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, payload...), nil
+}
+
+// ReadSchemaFile reads a schema, with its type from the extension of the file: .avsc, .proto or .json
+func ReadSchemaFile(path string) (sr.Schema, error) {
+	var schemaType sr.SchemaType
+	switch filepath.Ext(path) {
+	case ".avsc":
+		schemaType = sr.TypeAvro
+	case ".proto":
+		schemaType = sr.TypeProtobuf
+	case ".json":
+		schemaType = sr.TypeJSON
+	default:
+		return sr.Schema{}, fmt.Errorf("unknown type of schema '%s'. Use the .avsc extension for Avro, .proto for Protobuf or .json for JSON Schema", path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return sr.Schema{}, fmt.Errorf("error reading schema '%s': %v", path, err)
+	}
+	return sr.Schema{Schema: string(content), Type: schemaType}, nil
+}
+
+func protobufMessageByName(file protoreflect.FileDescriptor, name string) (protoreflect.MessageDescriptor, error) {
+	if name == "" {
+		if file.Messages().Len() == 0 {
+			return nil, errors.New("no message in the schema")
+		}
+		return file.Messages().Get(0), nil
+	}
+	if descriptor := findProtobufMessage(file.Messages(), protoreflect.FullName(name)); descriptor != nil {
+		return descriptor, nil
+	}
+	return nil, fmt.Errorf("no message '%s' in the schema", name)
+}
+
+func findProtobufMessage(messages protoreflect.MessageDescriptors, name protoreflect.FullName) protoreflect.MessageDescriptor {
+	for i := 0; i < messages.Len(); i++ {
+		if messages.Get(i).FullName() == name {
+			return messages.Get(i)
+		}
+		if descriptor := findProtobufMessage(messages.Get(i).Messages(), name); descriptor != nil {
+			return descriptor
+		}
+	}
+	return nil
+}
+
+// encodeJSON encodes JSON Schema payloads, which are plain JSON. The value is not validated against the schema.
+func encodeJSON(jsonValue []byte) ([]byte, error) {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, jsonValue); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	return compacted.Bytes(), nil
+}
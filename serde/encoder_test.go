@@ -0,0 +1,214 @@
+package serde
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+)
+
+const paymentSchema = `{
+	"type": "record",
+	"name": "Payment",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "amount", "type": "double"},
+		{"name": "quantity", "type": "int"},
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["PENDING", "PAID"]}},
+		{"name": "paid_at", "type": ["null", {"type": "long", "logicalType": "timestamp-millis"}], "default": null},
+		{"name": "tags", "type": {"type": "array", "items": "string"}},
+		{"name": "currency", "type": "string", "default": "EUR"}
+	]
+}`
+
+func TestSchemaEncoderAvro(t *testing.T) {
+	registry := srfake.New()
+	defer registry.Close()
+	registry.SeedSchema("item-value", 1, 10, sr.Schema{Schema: itemSchema})
+	registry.SeedSchema("orders-value", 1, 1, sr.Schema{
+		Schema:     orderSchema,
+		References: []sr.SchemaReference{{Name: "com.example.Item", Subject: "item-value", Version: 1}},
+	})
+	registry.SeedSchema("payments-value", 1, 2, sr.Schema{Schema: `{"type": "string"}`})
+	registry.SeedSchema("payments-value", 2, 3, sr.Schema{Schema: paymentSchema})
+
+	client, err := NewRegistryClient(RegistryConfig{URL: registry.URL()})
+	assert.NoError(t, err)
+	decoder := NewSchemaRegistryDeserializer(client)
+
+	tests := []struct {
+		name             string
+		spec             SchemaSpec
+		value            string
+		expectedDecoded  string
+		expectedErrorMsg string
+	}{
+		{
+			name:            "latest version with references",
+			spec:            SchemaSpec{Subject: "orders-value", Version: LatestVersion},
+			value:           `{"id": 42, "customer": "alice", "item": {"name": "book"}}`,
+			expectedDecoded: `{"id": 42, "customer": "alice", "item": {"name": "book"}}`,
+		},
+		{
+			name:            "union in the Avro JSON encoding",
+			spec:            SchemaSpec{Subject: "orders-value", Version: LatestVersion},
+			value:           `{"id": 42, "customer": {"string": "alice"}, "item": {"name": "book"}}`,
+			expectedDecoded: `{"id": 42, "customer": "alice", "item": {"name": "book"}}`,
+		},
+		{
+			name:            "missing field with a default",
+			spec:            SchemaSpec{Subject: "orders-value", Version: LatestVersion},
+			value:           `{"id": 42, "item": {"name": "book"}}`,
+			expectedDecoded: `{"id": 42, "customer": null, "item": {"name": "book"}}`,
+		},
+		{
+			name:            "given version",
+			spec:            SchemaSpec{Subject: "payments-value", Version: 1},
+			value:           `"hello"`,
+			expectedDecoded: `"hello"`,
+		},
+		{
+			name:            "enum, logical type and array",
+			spec:            SchemaSpec{Subject: "payments-value", Version: LatestVersion},
+			value:           `{"id": 1, "amount": 9.99, "quantity": 3, "status": "PAID", "paid_at": "2025-01-01T00:00:00Z", "tags": ["a", "b"]}`,
+			expectedDecoded: `{"id": 1, "amount": 9.99, "quantity": 3, "status": "PAID", "paid_at": "2025-01-01T00:00:00Z", "tags": ["a", "b"], "currency": "EUR"}`,
+		},
+		{
+			name:             "missing field",
+			spec:             SchemaSpec{Subject: "orders-value", Version: LatestVersion},
+			value:            `{"id": 42}`,
+			expectedErrorMsg: "error encoding data with schema 1: missing field 'item' of record com.example.Order",
+		},
+		{
+			name:             "wrong type",
+			spec:             SchemaSpec{Subject: "payments-value", Version: LatestVersion},
+			value:            `{"id": 1, "amount": 9.99, "quantity": 3, "status": "REFUNDED", "tags": []}`,
+			expectedErrorMsg: "field 'status': expected one of the symbols [PENDING PAID] of enum com.example.Status, got REFUNDED",
+		},
+		{
+			name:             "invalid JSON",
+			spec:             SchemaSpec{Subject: "payments-value", Version: 1},
+			value:            `hello`,
+			expectedErrorMsg: "invalid JSON",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder, err := NewSchemaEncoder(client, test.spec)
+			assert.NoError(t, err)
+			encoded, err := encoder.Encode([]byte(test.value))
+			if test.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, test.expectedErrorMsg)
+				return
+			}
+			assert.NoError(t, err)
+			decoded, err := decoder.Deserialize(encoded)
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expectedDecoded, decoded)
+		})
+	}
+
+	_, err = NewSchemaEncoder(client, SchemaSpec{Subject: "unknown-value", Version: LatestVersion})
+	assert.ErrorContains(t, err, "error fetching schema of subject 'unknown-value'")
+}
+
+func TestSchemaEncoderProtobuf(t *testing.T) {
+	registry := srfake.New()
+	defer registry.Close()
+	registry.SeedSchema("customer.proto", 1, 10, sr.Schema{Schema: customerProto, Type: sr.TypeProtobuf})
+	registry.SeedSchema("orders-value", 1, 1, sr.Schema{
+		Schema:     ordersProto,
+		Type:       sr.TypeProtobuf,
+		References: []sr.SchemaReference{{Name: "customer.proto", Subject: "customer.proto", Version: 1}},
+	})
+
+	client, err := NewRegistryClient(RegistryConfig{URL: registry.URL()})
+	assert.NoError(t, err)
+	decoder := NewSchemaRegistryDeserializer(client)
+
+	encoder, err := NewSchemaEncoder(client, SchemaSpec{Subject: "orders-value", Version: LatestVersion, ProtoMessage: "shop.Order"})
+	assert.NoError(t, err)
+	encoded, err := encoder.Encode([]byte(`{"item": "book", "quantity": 3, "customer": {"name": "alice"}, "createdAt": "2025-01-01T00:00:00Z"}`))
+	assert.NoError(t, err)
+	decoded, err := decoder.Deserialize(encoded)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"item": "book", "quantity": 3, "customer": {"name": "alice"}, "createdAt": "2025-01-01T00:00:00Z"}`, decoded)
+
+	encoder, err = NewSchemaEncoder(client, SchemaSpec{Subject: "orders-value", Version: LatestVersion, ProtoMessage: "shop.Order.Line"})
+	assert.NoError(t, err)
+	encoded, err = encoder.Encode([]byte(`{"sku": "B-1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 1, 4, 2, 0}, encoded[:8], "the indexes of the nested message follow the schema id")
+	decoded, err = decoder.Deserialize(encoded)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sku": "B-1"}`, decoded)
+
+	_, err = encoder.Encode([]byte(`{"unknown": 1}`))
+	assert.ErrorContains(t, err, "error encoding data with schema 1")
+
+	_, err = NewSchemaEncoder(client, SchemaSpec{Subject: "orders-value", Version: LatestVersion, ProtoMessage: "shop.Unknown"})
+	assert.ErrorContains(t, err, "no message 'shop.Unknown' in the schema")
+}
+
+func TestSchemaEncoderFile(t *testing.T) {
+	registry := srfake.New()
+	defer registry.Close()
+	client, err := NewRegistryClient(RegistryConfig{URL: registry.URL()})
+	assert.NoError(t, err)
+	decoder := NewSchemaRegistryDeserializer(client)
+
+	dir := t.TempDir()
+	avroFile := filepath.Join(dir, "greeting.avsc")
+	assert.NoError(t, os.WriteFile(avroFile, []byte(`{"type": "string"}`), 0644))
+	jsonFile := filepath.Join(dir, "greeting.json")
+	assert.NoError(t, os.WriteFile(jsonFile, []byte(`{"type": "object"}`), 0644))
+	unknownFile := filepath.Join(dir, "greeting.txt")
+	assert.NoError(t, os.WriteFile(unknownFile, []byte(`string`), 0644))
+
+	// The schema file is looked up, and only registered on demand
+	_, err = NewSchemaEncoder(client, SchemaSpec{Subject: "greetings-value", File: avroFile})
+	assert.ErrorIs(t, err, ErrSchemaNotRegistered)
+	assert.EqualError(t, err, "schema is not registered under subject 'greetings-value': "+avroFile)
+
+	encoder, err := NewSchemaEncoder(client, SchemaSpec{Subject: "greetings-value", File: avroFile, Register: true})
+	assert.NoError(t, err)
+	encoded, err := encoder.Encode([]byte(`"hello"`))
+	assert.NoError(t, err)
+	decoded, err := decoder.Deserialize(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, `"hello"`, decoded)
+
+	// The schema is registered once, and found once registered
+	_, err = NewSchemaEncoder(client, SchemaSpec{Subject: "greetings-value", File: avroFile, Register: true})
+	assert.NoError(t, err)
+	_, err = NewSchemaEncoder(client, SchemaSpec{Subject: "greetings-value", File: avroFile})
+	assert.NoError(t, err)
+	schemas, err := client.Schemas(t.Context(), "greetings-value")
+	assert.NoError(t, err)
+	assert.Len(t, schemas, 1)
+
+	// A schema which differs from the registered ones is not found either
+	otherFile := filepath.Join(dir, "other.avsc")
+	assert.NoError(t, os.WriteFile(otherFile, []byte(`{"type": "int"}`), 0644))
+	_, err = NewSchemaEncoder(client, SchemaSpec{Subject: "greetings-value", File: otherFile})
+	assert.ErrorIs(t, err, ErrSchemaNotRegistered)
+
+	encoder, err = NewSchemaEncoder(client, SchemaSpec{Subject: "greetings-json-value", File: jsonFile, Register: true})
+	assert.NoError(t, err)
+	encoded, err = encoder.Encode([]byte(`{ "text": "hello" }`))
+	assert.NoError(t, err)
+	decoded, err = decoder.Deserialize(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"text":"hello"}`, decoded)
+
+	_, err = NewSchemaEncoder(client, SchemaSpec{Subject: "greetings-value", File: unknownFile})
+	assert.ErrorContains(t, err, "unknown type of schema")
+
+	_, err = NewSchemaEncoder(client, SchemaSpec{Subject: "greetings-value", File: filepath.Join(dir, "missing.avsc")})
+	assert.ErrorContains(t, err, "error reading schema")
+}
@@ -0,0 +1,47 @@
+package serde
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// execTimeout bounds the run of the command of an ExecDeserializer, for each key or value
+const execTimeout = 10 * time.Second
+
+// ExecDeserializer pipes data to the standard input of a shell command, and uses its standard output as the
+// deserialized data. The command is run once per key or value.
+type ExecDeserializer struct {
+	command string
+}
+
+func NewExecDeserializer(command string) *ExecDeserializer {
+	return &ExecDeserializer{command: command}
+}
+
+func (d *ExecDeserializer) Deserialize(data []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+	defer cancel()
+
+	var command *exec.Cmd
+	if runtime.GOOS == "windows" {
+		command = exec.CommandContext(ctx, "cmd", "/C", d.command)
+	} else {
+		command = exec.CommandContext(ctx, "sh", "-c", d.command)
+	}
+	var stdout, stderr bytes.Buffer
+	command.Stdin = bytes.NewReader(data)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("error running '%s': %v: %s", d.command, err, message)
+		}
+		return "", fmt.Errorf("error running '%s': %v", d.command, err)
+	}
+	return strings.TrimSuffix(stdout.String(), "\n"), nil
+}
@@ -0,0 +1,138 @@
+package serde
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/twmb/franz-go/pkg/sr"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func (d *SchemaRegistryDeserializer) protobufDecoder(ctx context.Context, id int, schema sr.Schema) (schemaDecoder, error) {
+	file, err := compileProtobuf(ctx, d.client, id, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bounding the number of indexes by the nesting depth of the messages keeps a corrupt payload from allocating them
+	maxIndexes := max(messageDepth(file.Messages()), 1)
+	return func(payload []byte) (any, error) {
+		// The payload starts with the indexes of the message in the schema: the top level message, then nested ones
+		indexes, data, err := new(sr.ConfluentHeader).DecodeIndex(payload, maxIndexes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message indexes: %v", err)
+		}
+		descriptor, err := protobufMessage(file, indexes)
+		if err != nil {
+			return nil, err
+		}
+		return DecodeProtobuf(descriptor, data)
+	}, nil
+}
+
+// compileProtobuf compiles a schema of the registry together with the files it imports
+func compileProtobuf(ctx context.Context, client *sr.Client, id int, schema sr.Schema) (protoreflect.FileDescriptor, error) {
+	fileName := fmt.Sprintf("schema-%d.proto", id)
+	sources := map[string]string{fileName: schema.Schema}
+	if err := fetchProtobufReferences(ctx, client, schema.References, sources); err != nil {
+		return nil, err
+	}
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{Accessor: protocompile.SourceAccessorFromMap(sources)}),
+	}
+	files, err := compiler.Compile(ctx, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return files[0], nil
+}
+
+// fetchProtobufReferences fetches the files imported by a schema into sources, by import path
+func fetchProtobufReferences(ctx context.Context, client *sr.Client, references []sr.SchemaReference, sources map[string]string) error {
+	for _, reference := range references {
+		if _, ok := sources[reference.Name]; ok {
+			continue
+		}
+		referenced, err := client.SchemaByVersion(ctx, reference.Subject, reference.Version)
+		if err != nil {
+			return fmt.Errorf("error fetching referenced schema '%s' version %d: %v", reference.Subject, reference.Version, err)
+		}
+		sources[reference.Name] = referenced.Schema.Schema
+		if err := fetchProtobufReferences(ctx, client, referenced.References, sources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// messageDepth returns the nesting depth of messages, 1 for messages without nested ones
+func messageDepth(messages protoreflect.MessageDescriptors) int {
+	if messages.Len() == 0 {
+		return 0
+	}
+	depth := 0
+	for i := range messages.Len() {
+		depth = max(depth, messageDepth(messages.Get(i).Messages()))
+	}
+	return depth + 1
+}
+
+func protobufMessage(file protoreflect.FileDescriptor, indexes []int) (protoreflect.MessageDescriptor, error) {
+	messages := file.Messages()
+	var descriptor protoreflect.MessageDescriptor
+	for _, index := range indexes {
+		if index < 0 || index >= messages.Len() {
+			return nil, fmt.Errorf("no message at index %v of the schema", indexes)
+		}
+		descriptor = messages.Get(index)
+		messages = descriptor.Messages()
+	}
+	return descriptor, nil
+}
+
+// DecodeProtobuf decodes data as a message of type descriptor, to its JSON representation
+func DecodeProtobuf(descriptor protoreflect.MessageDescriptor, data []byte) (json.RawMessage, error) {
+	message := dynamicpb.NewMessage(descriptor)
+	if err := proto.Unmarshal(data, message); err != nil {
+		return nil, err
+	}
+	jsonValue, err := protojson.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	// protojson randomly adds spaces to its output, to prevent relying on it being stable
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, jsonValue); err != nil {
+		return nil, err
+	}
+	return compacted.Bytes(), nil
+}
+
+// protobufIndexes returns the indexes of a message in its file, the reverse of protobufMessage
+func protobufIndexes(descriptor protoreflect.MessageDescriptor) []int {
+	var indexes []int
+	var current protoreflect.Descriptor = descriptor
+	for {
+		indexes = append([]int{current.Index()}, indexes...)
+		parent, ok := current.Parent().(protoreflect.MessageDescriptor)
+		if !ok {
+			return indexes
+		}
+		current = parent
+	}
+}
+
+// EncodeProtobuf encodes the JSON representation of a message of type descriptor
+func EncodeProtobuf(descriptor protoreflect.MessageDescriptor, jsonValue []byte) ([]byte, error) {
+	message := dynamicpb.NewMessage(descriptor)
+	if err := protojson.Unmarshal(jsonValue, message); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(message)
+}
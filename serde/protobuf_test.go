@@ -0,0 +1,115 @@
+package serde
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const ordersProto = `syntax = "proto3";
+package shop;
+
+import "customer.proto";
+import "google/protobuf/timestamp.proto";
+
+message Ping {}
+
+message Order {
+	string item = 1;
+	int32 quantity = 2;
+	Customer customer = 3;
+	google.protobuf.Timestamp created_at = 4;
+
+	message Line {
+		string sku = 1;
+	}
+}`
+
+const customerProto = `syntax = "proto3";
+package shop;
+
+message Customer {
+	string name = 1;
+}`
+
+func TestSchemaRegistryDeserializerProtobuf(t *testing.T) {
+	registry := srfake.New()
+	defer registry.Close()
+	registry.SeedSchema("customer.proto", 1, 10, sr.Schema{Schema: customerProto, Type: sr.TypeProtobuf})
+	registry.SeedSchema("orders-value", 1, 1, sr.Schema{
+		Schema:     ordersProto,
+		Type:       sr.TypeProtobuf,
+		References: []sr.SchemaReference{{Name: "customer.proto", Subject: "customer.proto", Version: 1}},
+	})
+
+	var customer []byte
+	customer = protowire.AppendTag(customer, 1, protowire.BytesType)
+	customer = protowire.AppendString(customer, "alice")
+	var createdAt []byte
+	createdAt = protowire.AppendTag(createdAt, 1, protowire.VarintType)
+	createdAt = protowire.AppendVarint(createdAt, 1735689600)
+	var order []byte
+	order = protowire.AppendTag(order, 1, protowire.BytesType)
+	order = protowire.AppendString(order, "book")
+	order = protowire.AppendTag(order, 2, protowire.VarintType)
+	order = protowire.AppendVarint(order, 3)
+	order = protowire.AppendTag(order, 3, protowire.BytesType)
+	order = protowire.AppendBytes(order, customer)
+	order = protowire.AppendTag(order, 4, protowire.BytesType)
+	order = protowire.AppendBytes(order, createdAt)
+	var line []byte
+	line = protowire.AppendTag(line, 1, protowire.BytesType)
+	line = protowire.AppendString(line, "sku-1")
+
+	client, err := NewRegistryClient(RegistryConfig{URL: registry.URL()})
+	assert.NoError(t, err)
+	decoder := NewSchemaRegistryDeserializer(client)
+
+	protobufWireFormat := func(indexes []int, data []byte) []byte {
+		payload, err := new(sr.ConfluentHeader).AppendEncode(nil, 1, indexes)
+		assert.NoError(t, err)
+		return append(payload, data...)
+	}
+
+	decoded, err := decoder.Deserialize(protobufWireFormat([]int{1}, order))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"item": "book", "quantity": 3, "customer": {"name": "alice"}, "createdAt": "2025-01-01T00:00:00Z"}`, decoded)
+
+	decoded, err = decoder.Deserialize(protobufWireFormat([]int{1, 0}, line))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"sku":"sku-1"}`, decoded)
+
+	// The first message is encoded without indexes
+	decoded, err = decoder.Deserialize(protobufWireFormat([]int{0}, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, decoded)
+
+	_, err = decoder.Deserialize(protobufWireFormat([]int{5}, order))
+	assert.ErrorContains(t, err, "no message at index [5] of the schema")
+
+	// Order.Line is the deepest message, so a payload with more indexes is rejected before they are allocated
+	_, err = decoder.Deserialize(protobufWireFormat([]int{1, 0, 0}, line))
+	assert.ErrorContains(t, err, "invalid message indexes")
+	_, err = decoder.Deserialize(append([]byte{0, 0, 0, 0, 1}, protowire.AppendVarint(nil, protowire.EncodeZigZag(1<<40))...))
+	assert.ErrorContains(t, err, "invalid message indexes")
+}
+
+func TestSchemaRegistryDeserializerJSONSchema(t *testing.T) {
+	registry := srfake.New()
+	defer registry.Close()
+	registry.SeedSchema("events-value", 1, 1, sr.Schema{Schema: `{"type": "object"}`, Type: sr.TypeJSON})
+
+	client, err := NewRegistryClient(RegistryConfig{URL: registry.URL()})
+	assert.NoError(t, err)
+	decoder := NewSchemaRegistryDeserializer(client)
+
+	decoded, err := decoder.Deserialize(wireFormat(t, 1, []byte(`{"event": "created", "id": 1}`)))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"event":"created","id":1}`, decoded)
+
+	_, err = decoder.Deserialize(wireFormat(t, 1, []byte(`{"event": `)))
+	assert.ErrorContains(t, err, "error decoding data with schema 1: invalid JSON")
+}
@@ -0,0 +1,104 @@
+package serde
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/sr"
+)
+
+const requestTimeout = 10 * time.Second
+
+// ErrNotWireFormat is returned when decoding data which does not start with the magic byte and schema id of the
+// Confluent wire format
+var ErrNotWireFormat = errors.New("data is not in the schema registry wire format")
+
+// RegistryConfig is the schema registry of a cluster, with optional basic authentication
+type RegistryConfig struct {
+	URL      string
+	Username string
+	Password string
+}
+
+func NewRegistryClient(config RegistryConfig) (*sr.Client, error) {
+	opts := []sr.ClientOpt{sr.URLs(config.URL)}
+	if config.Username != "" || config.Password != "" {
+		opts = append(opts, sr.BasicAuth(config.Username, config.Password))
+	}
+	return sr.NewClient(opts...)
+}
+
+type schemaDecoder func(payload []byte) (any, error)
+
+// decodeJSON decodes JSON Schema payloads, which are plain JSON
+func decodeJSON(payload []byte) (any, error) {
+	if !json.Valid(payload) {
+		return nil, errors.New("invalid JSON")
+	}
+	return json.RawMessage(payload), nil
+}
+
+// SchemaRegistryDeserializer decodes Avro, Protobuf and JSON Schema data in the Confluent wire format to JSON.
+// Schemas are fetched from the registry by id and cached.
+type SchemaRegistryDeserializer struct {
+	client  *sr.Client
+	schemas map[int]schemaDecoder
+}
+
+func NewSchemaRegistryDeserializer(client *sr.Client) *SchemaRegistryDeserializer {
+	return &SchemaRegistryDeserializer{client: client, schemas: make(map[int]schemaDecoder)}
+}
+
+// Deserialize returns the JSON representation of data, or ErrNotWireFormat if data was not serialized with a schema
+func (d *SchemaRegistryDeserializer) Deserialize(data []byte) (string, error) {
+	id, payload, err := new(sr.ConfluentHeader).DecodeID(data)
+	if err != nil {
+		return "", ErrNotWireFormat
+	}
+	decode, err := d.decoder(id)
+	if err != nil {
+		return "", err
+	}
+	value, err := decode(payload)
+	if err != nil {
+		return "", fmt.Errorf("error decoding data with schema %d: %v", id, err)
+	}
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("error decoding data with schema %d: %v", id, err)
+	}
+	return string(jsonValue), nil
+}
+
+func (d *SchemaRegistryDeserializer) decoder(id int) (schemaDecoder, error) {
+	if decode, ok := d.schemas[id]; ok {
+		return decode, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	schema, err := d.client.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching schema %d from the schema registry: %v", id, err)
+	}
+
+	var decode schemaDecoder
+	switch schema.Type {
+	case sr.TypeAvro:
+		decode, err = d.avroDecoder(ctx, schema)
+	case sr.TypeProtobuf:
+		decode, err = d.protobufDecoder(ctx, id, schema)
+	case sr.TypeJSON:
+		decode = decodeJSON
+	default:
+		err = fmt.Errorf("unsupported schema type %s", schema.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %d: %v", id, err)
+	}
+	d.schemas[id] = decode
+	return decode, nil
+}
@@ -0,0 +1,95 @@
+package serde
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/sr"
+	"github.com/twmb/franz-go/pkg/sr/srfake"
+)
+
+const orderSchema = `{
+	"type": "record",
+	"name": "Order",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "customer", "type": ["null", "string"], "default": null},
+		{"name": "item", "type": "com.example.Item"}
+	]
+}`
+
+const itemSchema = `{"type": "record", "name": "Item", "namespace": "com.example", "fields": [{"name": "name", "type": "string"}]}`
+
+// basicAuth is the Authorization header of the user "user" with the password "password"
+const basicAuth = "Basic dXNlcjpwYXNzd29yZA=="
+
+func wireFormat(t *testing.T, id int, payload []byte) []byte {
+	data, err := new(sr.ConfluentHeader).AppendEncode(nil, id, nil)
+	assert.NoError(t, err)
+	return append(data, payload...)
+}
+
+func TestSchemaRegistryDeserializerAvro(t *testing.T) {
+	registry := srfake.New(srfake.WithAuth(basicAuth))
+	defer registry.Close()
+	registry.SeedSchema("item-value", 1, 10, sr.Schema{Schema: itemSchema})
+	registry.SeedSchema("orders-value", 1, 1, sr.Schema{
+		Schema:     orderSchema,
+		References: []sr.SchemaReference{{Name: "com.example.Item", Subject: "item-value", Version: 1}},
+	})
+	registry.SeedSchema("greetings-value", 1, 2, sr.Schema{Schema: `{"type": "string"}`})
+
+	cache := &avro.SchemaCache{}
+	_, err := avro.ParseWithCache(itemSchema, "", cache)
+	assert.NoError(t, err)
+	writerSchema, err := avro.ParseWithCache(orderSchema, "", cache)
+	assert.NoError(t, err)
+	order, err := avro.Marshal(writerSchema, map[string]any{"id": int64(42), "customer": "alice", "item": map[string]any{"name": "book"}})
+	assert.NoError(t, err)
+	greeting, err := avro.Marshal(avro.MustParse(`{"type": "string"}`), "hello")
+	assert.NoError(t, err)
+
+	client, err := NewRegistryClient(RegistryConfig{URL: registry.URL(), Username: "user", Password: "password"})
+	assert.NoError(t, err)
+	decoder := NewSchemaRegistryDeserializer(client)
+
+	decoded, err := decoder.Deserialize(wireFormat(t, 1, order))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": 42, "customer": "alice", "item": {"name": "book"}}`, decoded)
+
+	decoded, err = decoder.Deserialize(wireFormat(t, 2, greeting))
+	assert.NoError(t, err)
+	assert.Equal(t, `"hello"`, decoded)
+
+	_, err = decoder.Deserialize([]byte("plain text"))
+	assert.ErrorIs(t, err, ErrNotWireFormat)
+
+	_, err = decoder.Deserialize(wireFormat(t, 3, greeting))
+	assert.ErrorContains(t, err, "error fetching schema 3 from the schema registry")
+
+	_, err = decoder.Deserialize(wireFormat(t, 2, []byte{0x10}))
+	assert.ErrorContains(t, err, "error decoding data with schema 2")
+
+	// Schemas are cached by id, the registry is not needed anymore
+	registry.Intercept(func(w http.ResponseWriter, r *http.Request) bool {
+		w.WriteHeader(http.StatusInternalServerError)
+		return true
+	})
+	decoded, err = decoder.Deserialize(wireFormat(t, 1, order))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": 42, "customer": "alice", "item": {"name": "book"}}`, decoded)
+}
+
+func TestSchemaRegistryDeserializerBasicAuth(t *testing.T) {
+	registry := srfake.New(srfake.WithAuth(basicAuth))
+	defer registry.Close()
+	registry.SeedSchema("greetings-value", 1, 1, sr.Schema{Schema: `{"type": "string"}`})
+
+	client, err := NewRegistryClient(RegistryConfig{URL: registry.URL(), Username: "user", Password: "wrong"})
+	assert.NoError(t, err)
+	_, err = NewSchemaRegistryDeserializer(client).Deserialize(wireFormat(t, 1, []byte{0x02, 'a'}))
+	assert.ErrorContains(t, err, "User not authorized")
+}
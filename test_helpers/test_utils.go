@@ -5,10 +5,17 @@ import (
 	"context"
 	"fmt"
 	"github.com/Vidalee/kacao/cmd"
+	"github.com/bufbuild/protocompile"
+	"github.com/hamba/avro/v2"
 	"github.com/spf13/pflag"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sr"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -106,6 +113,8 @@ func ExecuteCommandWrapper(args []string) (string, error) {
 func ResetSubCommandFlagValues(root *cobra.Command) {
 	for _, c := range root.Commands() {
 		c.Flags().VisitAll(func(f *pflag.Flag) {
+			// Flag groups (required, mutually exclusive) are validated on Changed, which Value.Set does not reset
+			f.Changed = false
 			sliceValueType := reflect.TypeOf((*pflag.SliceValue)(nil)).Elem()
 			if reflect.TypeOf(f.Value).Implements(sliceValueType) {
 				defValue := strings.Trim(f.DefValue, "[]")
@@ -177,6 +186,73 @@ func ProduceMessageWithHeadersAndKey(t *testing.T, cl *kgo.Client, topic string,
 	}
 }
 
+func ProduceRecord(t *testing.T, cl *kgo.Client, record *kgo.Record) {
+	t.Helper()
+
+	results := cl.ProduceSync(context.Background(), record)
+	for _, result := range results {
+		assert.NoError(t, result.Err, "Failed to produce message to topic %s", record.Topic)
+	}
+}
+
 func StringPtr(s string) *string {
 	return &s
 }
+
+// ProduceTransaction produces values to topic in a single transaction, then commits or aborts it
+func ProduceTransaction(t *testing.T, brokers []string, topic string, values []string, commit bool) {
+	t.Helper()
+
+	cl, err := kgo.NewClient(
+		kgo.SeedBrokers(brokers...),
+		kgo.TransactionalID(fmt.Sprintf("kacao-test-%s-%d", topic, time.Now().UnixNano())),
+	)
+	assert.NoError(t, err)
+	defer cl.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, cl.BeginTransaction())
+	for _, value := range values {
+		results := cl.ProduceSync(ctx, &kgo.Record{Topic: topic, Value: []byte(value)})
+		for _, result := range results {
+			assert.NoError(t, result.Err, "Failed to produce message to topic %s", topic)
+		}
+	}
+	assert.NoError(t, cl.EndTransaction(ctx, kgo.TransactionEndTry(commit)))
+}
+
+// AvroWireFormat serializes value with the Avro schema, prefixed with the Confluent wire format header of the schema id
+func AvroWireFormat(t *testing.T, id int, schema string, value any) []byte {
+	t.Helper()
+
+	data, err := avro.Marshal(avro.MustParse(schema), value)
+	assert.NoError(t, err)
+	header, err := new(sr.ConfluentHeader).AppendEncode(nil, id, nil)
+	assert.NoError(t, err)
+	return append(header, data...)
+}
+
+// ProtoDescriptorSet compiles a Protobuf file and writes it with its imports to a descriptor set, returning its path
+func ProtoDescriptorSet(t *testing.T, source string) string {
+	t.Helper()
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{"schema.proto": source}),
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), "schema.proto")
+	assert.NoError(t, err)
+
+	descriptorSet := &descriptorpb.FileDescriptorSet{}
+	for i := 0; i < files[0].Imports().Len(); i++ {
+		descriptorSet.File = append(descriptorSet.File, protodesc.ToFileDescriptorProto(files[0].Imports().Get(i).FileDescriptor))
+	}
+	descriptorSet.File = append(descriptorSet.File, protodesc.ToFileDescriptorProto(files[0]))
+	content, err := proto.Marshal(descriptorSet)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "schema.pb")
+	assert.NoError(t, os.WriteFile(path, content, 0644))
+	return path
+}